@@ -0,0 +1,42 @@
+package selectel
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBlastRadius is returned when a call would delete or replace more RR sets than allowed by Provider.BlastRadius.
+var ErrBlastRadius = errors.New("too many RR sets affected")
+
+// BlastRadius limits the number of RR sets a single SetRecords or DeleteRecords call may delete or replace.
+// Calls exceeding the limits are aborted before any changes are made. Zero values mean no limit.
+type BlastRadius struct {
+	// MaxRRSets is the maximum number of affected RR sets.
+	MaxRRSets int
+	// MaxPercent is the maximum percentage of affected RR sets in the zone.
+	MaxPercent float64
+}
+
+type bulkChangesKey struct{}
+
+// AllowBulkChanges returns a context which disables BlastRadius limits for intentional bulk changes.
+func AllowBulkChanges(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bulkChangesKey{}, true)
+}
+
+func (r BlastRadius) check(ctx context.Context, affected, total int) error {
+	if allowed, _ := ctx.Value(bulkChangesKey{}).(bool); allowed {
+		return nil
+	}
+
+	if r.MaxRRSets > 0 && affected > r.MaxRRSets {
+		return errors.Wrapf(ErrBlastRadius, "%d RR sets affected, limit is %d", affected, r.MaxRRSets)
+	}
+
+	if r.MaxPercent > 0 && total > 0 && float64(affected)*100 > r.MaxPercent*float64(total) {
+		return errors.Wrapf(ErrBlastRadius, "%d of %d RR sets affected, limit is %g%%", affected, total, r.MaxPercent)
+	}
+
+	return nil
+}
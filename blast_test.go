@@ -0,0 +1,98 @@
+package selectel_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_BlastRadius(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) *selecteltest.Client {
+		client := selecteltest.NewClient("zone1.org.")
+		for _, name := range []string{"a", "b", "c", "d"} {
+			require.NoError(t, client.Put("zone1.org.", &selectel.RRSet{
+				Key: selectel.RRSetKey{Name: name, Type: "A"},
+				TTL: time.Hour,
+				RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), nil},
+			}))
+		}
+
+		return client
+	}
+
+	address := func(name string, ip string) libdns.Record {
+		return libdns.Address{Name: name, TTL: time.Hour, IP: netip.MustParseAddr(ip)}
+	}
+
+	t.Run("max RR sets", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+		provider.BlastRadius = selectel.BlastRadius{MaxRRSets: 1}
+
+		records := []libdns.Record{libdns.RR{Name: "a", Type: "A"}, libdns.RR{Name: "b", Type: "A"}}
+		_, err := provider.DeleteRecords(ctx, "zone1.org.", records)
+		assert.ErrorIs(t, err, selectel.ErrBlastRadius)
+		assert.Len(t, client.RRSets("zone1.org."), 4)
+
+		_, err = provider.DeleteRecords(selectel.AllowBulkChanges(ctx), "zone1.org.", records)
+		require.NoError(t, err)
+		assert.Len(t, client.RRSets("zone1.org."), 2)
+	})
+
+	t.Run("unowned RR sets are not counted", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+		provider.OwnerID = "owner1"
+		provider.BlastRadius = selectel.BlastRadius{MaxRRSets: 1}
+
+		_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{address("e", "2.2.2.2")})
+		require.NoError(t, err)
+
+		_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{
+			libdns.RR{Name: "a", Type: "A"},
+			libdns.RR{Name: "b", Type: "A"},
+			libdns.RR{Name: "e", Type: "A"},
+			libdns.RR{Name: "_libdns-a.e", Type: "TXT"},
+		})
+		assert.ErrorIs(t, err, selectel.ErrNotOwned)
+		assert.NotErrorIs(t, err, selectel.ErrBlastRadius)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Len(t, sets, 4)
+		assert.NotContains(t, sets, selectel.RRSetKey{Name: "e", Type: "A"})
+	})
+
+	t.Run("max percent", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+		provider.BlastRadius = selectel.BlastRadius{MaxPercent: 50}
+
+		_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+			address("a", "2.2.2.2"),
+			address("b", "2.2.2.2"),
+			address("c", "1.1.1.1"),
+			address("e", "2.2.2.2"),
+		})
+		require.NoError(t, err)
+
+		_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+			address("a", "3.3.3.3"),
+			address("b", "3.3.3.3"),
+			address("c", "3.3.3.3"),
+		})
+		assert.ErrorIs(t, err, selectel.ErrBlastRadius)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Len(t, sets, 5)
+		assert.Equal(t, selectel.SetOf("1.1.1.1"), sets[selectel.RRSetKey{Name: "c", Type: "A"}].RRs[0])
+	})
+}
@@ -0,0 +1,234 @@
+package selectel
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const redacted = "REDACTED"
+
+var (
+	redactedHeaders = []string{"X-Auth-Token", "X-Subject-Token"}
+	redactedFields  = []string{"password"}
+)
+
+// Interaction is a single HTTP request/response pair recorded by the recording transport.
+type Interaction struct {
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	StatusCode     int         `json:"status_code"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+}
+
+type recordingTransport struct {
+	next http.RoundTripper
+	enc  *json.Encoder
+	mu   sync.Mutex
+}
+
+// NewRecordingTransport creates a transport which writes each request/response pair
+// passing through next as a JSON line (see Interaction) to w.
+// Auth tokens and passwords are redacted. http.DefaultTransport is used if next is nil.
+// Use it with WithHTTPClient.
+func NewRecordingTransport(w io.Writer, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &recordingTransport{
+		next: next,
+		enc:  json.NewEncoder(w),
+	}
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "read request body")
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "read response body")
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := t.enc.Encode(Interaction{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeader:  redactHeader(req.Header),
+		RequestBody:    redactBody(reqBody),
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: redactHeader(resp.Header),
+		ResponseBody:   redactBody(respBody),
+	}); err != nil {
+		return nil, errors.Wrap(err, "write interaction")
+	}
+
+	return resp, nil
+}
+
+type replayTransport struct {
+	interactions []Interaction
+	used         []bool
+	mu           sync.Mutex
+}
+
+// NewReplayTransport creates a transport serving responses from interactions
+// previously written by the recording transport to r.
+// A request is served by the first unused interaction with the same method, path and query;
+// host is ignored, so that traffic recorded against any endpoint can be replayed.
+// Note that the client requests a new token if the recorded one has expired.
+func NewReplayTransport(r io.Reader) (http.RoundTripper, error) {
+	var interactions []Interaction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(line, &interaction); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal interaction %d", len(interactions)+1)
+		}
+
+		interactions = append(interactions, interaction)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read interactions")
+	}
+
+	return &replayTransport{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Method != req.Method {
+			continue
+		}
+
+		recorded, err := url.Parse(interaction.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse interaction %d URL", i+1)
+		}
+
+		if recorded.RequestURI() != req.URL.RequestURI() {
+			continue
+		}
+
+		t.used[i] = true
+
+		header := interaction.ResponseHeader.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+
+		return &http.Response{
+			Status:        http.StatusText(interaction.StatusCode),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, errors.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+}
+
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range redactedHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, redacted)
+		}
+	}
+
+	return header
+}
+
+func redactBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	if !redactValue(value) {
+		return string(body)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(data)
+}
+
+// redactValue replaces string values of redacted fields in place and reports whether any were found.
+func redactValue(value any) (found bool) {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if _, ok := item.(string); ok && slices.Contains(redactedFields, key) {
+				value[key] = redacted
+				found = true
+				continue
+			}
+
+			found = redactValue(item) || found
+		}
+
+	case []any:
+		for _, item := range value {
+			found = redactValue(item) || found
+		}
+	}
+
+	return
+}
@@ -0,0 +1,84 @@
+package selectel_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+var testCreds = selectel.Credentials{
+	Username:    "user",
+	Password:    "s3cr3t",
+	AccountID:   "123456",
+	ProjectName: "project",
+}
+
+func TestRecordingTransport(t *testing.T) {
+	server := selecteltest.NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+
+	var cassette bytes.Buffer
+	client := &http.Client{Transport: selectel.NewRecordingTransport(&cassette, server.Client().Transport)}
+	provider := selectel.NewProvider(selectel.NewClient(testCreds, append(server.Options(), selectel.WithHTTPClient(client))...))
+	recorded := runCassetteScenario(t, provider)
+
+	assert.NotContains(t, cassette.String(), testCreds.Password)
+	assert.Contains(t, cassette.String(), `"X-Subject-Token":["REDACTED"]`)
+	assert.Contains(t, cassette.String(), `"X-Auth-Token":["REDACTED"]`)
+
+	transport, err := selectel.NewReplayTransport(&cassette)
+	require.NoError(t, err)
+
+	// default API URLs are used, the host is ignored by the replay transport
+	provider = selectel.NewProvider(selectel.NewClient(testCreds, selectel.WithHTTPClient(&http.Client{Transport: transport})))
+	replayed := runCassetteScenario(t, provider)
+	assert.Equal(t, recorded, replayed)
+}
+
+func TestReplayTransport(t *testing.T) {
+	file, err := os.Open("testdata/append.jsonl")
+	require.NoError(t, err)
+	defer file.Close()
+
+	transport, err := selectel.NewReplayTransport(file)
+	require.NoError(t, err)
+
+	provider := selectel.NewProvider(selectel.NewClient(testCreds, selectel.WithHTTPClient(&http.Client{Transport: transport})))
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "rrset1", TTL: time.Minute, Text: `4"5"6`},
+		libdns.TXT{Name: "rrset1", TTL: time.Minute, Text: "HELLO"},
+	}, runCassetteScenario(t, provider))
+
+	_, err = provider.GetRecords(context.Background(), "zone1.org.")
+	assert.ErrorContains(t, err, "no recorded interaction for GET")
+}
+
+func runCassetteScenario(t *testing.T, provider *selectel.Provider) []libdns.Record {
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "rrset1", Text: "HELLO"},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "rrset1", Text: `4"5"6`},
+	})
+	require.NoError(t, err)
+
+	records, err := provider.GetRecords(ctx, "zone1.org.")
+	require.NoError(t, err)
+	return records
+}
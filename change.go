@@ -0,0 +1,82 @@
+package selectel
+
+import (
+	"context"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+)
+
+// ChangeOp is a type of RR set change.
+type ChangeOp int
+
+const (
+	ChangeCreate ChangeOp = iota
+	ChangeUpdate
+	ChangeDelete
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeCreate:
+		return "create"
+	case ChangeUpdate:
+		return "update"
+	case ChangeDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+func (op ChangeOp) MarshalText() ([]byte, error) {
+	return []byte(op.String()), nil
+}
+
+func (op *ChangeOp) UnmarshalText(text []byte) error {
+	for _, value := range []ChangeOp{ChangeCreate, ChangeUpdate, ChangeDelete} {
+		if value.String() == string(text) {
+			*op = value
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown change op %q", text)
+}
+
+// ChangeEvent describes an attempted change of an RR set.
+type ChangeEvent struct {
+	Op   ChangeOp
+	Zone string
+	Key  RRSetKey
+	// TTL is the TTL of the RR set after the change (before the change for ChangeDelete).
+	TTL time.Duration
+	// BeforeTTL is the TTL of the RR set before the change (zero for ChangeCreate).
+	BeforeTTL time.Duration
+
+	// Before contains enabled records of the RR set before the change (empty for ChangeCreate).
+	Before []libdns.Record
+	// After contains enabled records of the RR set after the change (empty for ChangeDelete).
+	After []libdns.Record
+
+	// BeforeDisabled and AfterDisabled contain disabled records of the RR set before and after the change.
+	BeforeDisabled []libdns.Record
+	AfterDisabled  []libdns.Record
+
+	// Err is set if the change failed. In this case the RR set is left as in Before.
+	Err error
+}
+
+// Observer receives RR set changes made by Provider.
+// OnChange is called synchronously after each create, update or delete request, including failed ones.
+type Observer interface {
+	OnChange(ctx context.Context, event ChangeEvent)
+}
+
+// ObserverFunc is a function implementing Observer.
+type ObserverFunc func(ctx context.Context, event ChangeEvent)
+
+func (fn ObserverFunc) OnChange(ctx context.Context, event ChangeEvent) {
+	fn(ctx, event)
+}
@@ -0,0 +1,70 @@
+package selectel_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Observer(t *testing.T) {
+	ctx := context.Background()
+	client := selecteltest.NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.", &selectel.RRSet{
+		Key: selectel.RRSetKey{Name: "rrset1", Type: "A"},
+		TTL: time.Hour,
+		RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), nil},
+	}))
+
+	var events []selectel.ChangeEvent
+	provider := selectel.NewProvider(client)
+	provider.Observer = selectel.ObserverFunc(func(ctx context.Context, event selectel.ChangeEvent) {
+		events = append(events, event)
+	})
+
+	rrset1 := selectel.RRSetKey{Name: "rrset1", Type: "A"}
+	rrset2 := selectel.RRSetKey{Name: "rrset2", Type: "TXT"}
+	a1 := libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.MustParseAddr("1.1.1.1")}
+	a2 := libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.MustParseAddr("2.2.2.2")}
+	txt := libdns.TXT{Name: "rrset2", TTL: time.Minute, Text: "hello"}
+
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{a2, txt})
+	require.NoError(t, err)
+	assert.Equal(t, []selectel.ChangeEvent{
+		{Op: selectel.ChangeCreate, Zone: "zone1.org.", Key: rrset2, TTL: time.Minute, After: []libdns.Record{txt}},
+		{Op: selectel.ChangeUpdate, Zone: "zone1.org.", Key: rrset1, TTL: time.Hour, BeforeTTL: time.Hour, Before: []libdns.Record{a1}, After: []libdns.Record{a1, a2}},
+	}, events)
+
+	events = nil
+	_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{txt})
+	require.NoError(t, err)
+	assert.Equal(t, []selectel.ChangeEvent{
+		{Op: selectel.ChangeDelete, Zone: "zone1.org.", Key: rrset2, TTL: time.Minute, BeforeTTL: time.Minute, Before: []libdns.Record{txt}},
+	}, events)
+
+	events = nil
+	failure := errors.New("failure")
+	client.SetFail(func(op selecteltest.Op, zone string, key selectel.RRSetKey) error {
+		if op == selecteltest.OpUpdateRRSet {
+			return failure
+		}
+
+		return nil
+	})
+
+	_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{a2})
+	require.Error(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, selectel.ChangeUpdate, events[0].Op)
+	assert.Equal(t, []libdns.Record{a1, a2}, events[0].Before)
+	assert.Equal(t, []libdns.Record{a2}, events[0].After)
+	assert.ErrorIs(t, events[0].Err, failure)
+}
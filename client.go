@@ -3,14 +3,17 @@ package selectel
 import (
 	"context"
 	"iter"
+	"log/slog"
 	"maps"
 	"slices"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 	v2 "github.com/selectel/domains-go/pkg/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -38,40 +41,53 @@ func (c *Credentials) Validate() error {
 }
 
 type client struct {
-	dns   DNSClient
-	limit int
-	zones map[string]string
-	mu    sync.RWMutex
+	dns    DNSClient
+	log    *slog.Logger
+	tracer trace.Tracer
+	limit  int
+	zones  map[string]string
+	mu     sync.RWMutex
 }
 
 // NewClient creates a Selectel DNS API client.
 // It handles retries and obtaining a project-scoped token for managing DNS zones & records.
-func NewClient(creds Credentials) Client {
+func NewClient(creds Credentials, opts ...Option) Client {
+	o := newOptions(opts)
 	return &client{
-		dns:   newWrapper(creds),
-		limit: defaultLimit,
-		zones: make(map[string]string),
+		dns:    newWrapper(creds, o),
+		log:    o.logger,
+		tracer: newTracer(o.tracerProvider),
+		limit:  defaultLimit,
+		zones:  make(map[string]string),
 	}
 }
 
 // GetZones retrieves zone names and IDs for the project and caches them.
+// Zone names are returned in lexicographical order.
 func (c *client) GetZones(ctx context.Context) ([]string, error) {
 	zoneIDs, err := c.getZoneIDs(ctx, "")
 	if err != nil {
 		return nil, errors.Wrap(err, "get zone IDs")
 	}
 
-	return slices.Collect(maps.Keys(zoneIDs)), nil
+	return slices.Sorted(maps.Keys(zoneIDs)), nil
 }
 
 // GetRRSets retrieves RR sets for the specified zone name.
-func (c *client) GetRRSets(ctx context.Context, zone string) (map[RRSetKey]*RRSet, error) {
+func (c *client) GetRRSets(ctx context.Context, zone string) (_ map[RRSetKey]*RRSet, err error) {
+	ctx, span := startSpan(ctx, c.tracer, "GetRRSets", zoneKey.String(zone))
+	defer func() { endSpan(span, err) }()
+
 	zoneID, err := c.getZoneID(ctx, zone)
 	if err != nil {
 		return nil, errors.Wrap(err, "get zone ID")
 	}
 
-	iterator := iterate(c, func(params *map[string]string) (v2.Listable[v2.RRSet], error) {
+	defer c.logCall(ctx, "GetRRSets", time.Now(), &err, slog.String("zone", zone))
+	iterator := iterate(c, func(params *map[string]string) (_ v2.Listable[v2.RRSet], err error) {
+		ctx, span := startSpan(ctx, c.tracer, "ListRRSets", zoneKey.String(zone), offsetKey.String((*params)["offset"]))
+		defer func() { endSpan(span, err) }()
+
 		return c.dns.ListRRSets(ctx, zoneID, params)
 	})
 
@@ -90,12 +106,17 @@ func (c *client) GetRRSets(ctx context.Context, zone string) (map[RRSetKey]*RRSe
 
 // CreateRRSet creates a RR set in the specified zone name.
 // If successful, set ID will be set in the provided set.
-func (c *client) CreateRRSet(ctx context.Context, zone string, set *RRSet) error {
+func (c *client) CreateRRSet(ctx context.Context, zone string, set *RRSet) (err error) {
+	ctx, span := startSpan(ctx, c.tracer, "CreateRRSet", zoneKey.String(zone), rrsetKey.String(set.Key.String()))
+	defer func() { endSpan(span, err) }()
+
 	zoneID, err := c.getZoneID(ctx, zone)
 	if err != nil {
 		return errors.Wrap(err, "get zone ID")
 	}
 
+	defer c.logCall(ctx, "CreateRRSet", time.Now(), &err, slog.String("zone", zone), slog.String("key", set.Key.String()))
+
 	rrs, err := c.dns.CreateRRSet(ctx, zoneID, set.toSelectel(zone))
 	if err != nil {
 		return err
@@ -106,25 +127,45 @@ func (c *client) CreateRRSet(ctx context.Context, zone string, set *RRSet) error
 }
 
 // UpdateRRSet updates a RR set in the specified zone name.
-func (c *client) UpdateRRSet(ctx context.Context, zone string, set *RRSet) error {
+func (c *client) UpdateRRSet(ctx context.Context, zone string, set *RRSet) (err error) {
+	ctx, span := startSpan(ctx, c.tracer, "UpdateRRSet", zoneKey.String(zone), rrsetKey.String(set.Key.String()))
+	defer func() { endSpan(span, err) }()
+
 	zoneID, err := c.getZoneID(ctx, zone)
 	if err != nil {
 		return errors.Wrap(err, "get zone ID")
 	}
 
+	defer c.logCall(ctx, "UpdateRRSet", time.Now(), &err, slog.String("zone", zone), slog.String("key", set.Key.String()))
+
 	return c.dns.UpdateRRSet(ctx, zoneID, set.ID, set.toSelectel(zone))
 }
 
 // DeleteRRSet deletes a RR set with the specified ID in the specified zone name.
-func (c *client) DeleteRRSet(ctx context.Context, zone string, setID string) error {
+func (c *client) DeleteRRSet(ctx context.Context, zone string, setID string) (err error) {
+	ctx, span := startSpan(ctx, c.tracer, "DeleteRRSet", zoneKey.String(zone), rrsetIDKey.String(setID))
+	defer func() { endSpan(span, err) }()
+
 	zoneID, err := c.getZoneID(ctx, zone)
 	if err != nil {
 		return errors.Wrap(err, "get zone ID")
 	}
 
+	defer c.logCall(ctx, "DeleteRRSet", time.Now(), &err, slog.String("zone", zone), slog.String("id", setID))
+
 	return c.dns.DeleteRRSet(ctx, zoneID, setID)
 }
 
+func (c *client) logCall(ctx context.Context, method string, startedAt time.Time, err *error, attrs ...slog.Attr) {
+	if c.log == nil {
+		return
+	}
+
+	c.log.LogAttrs(ctx, slog.LevelDebug, method, append(attrs,
+		slog.Duration("duration", time.Since(startedAt)),
+		errorAttr(*err))...)
+}
+
 func (c *client) getZoneID(ctx context.Context, name string) (string, error) {
 	c.mu.RLock()
 	zoneID, ok := c.zones[name]
@@ -148,11 +189,14 @@ func (c *client) getZoneID(ctx context.Context, name string) (string, error) {
 }
 
 func (c *client) getZoneIDs(ctx context.Context, name string) (map[string]string, error) {
-	iterator := iterate(c, func(params *map[string]string) (v2.Listable[v2.Zone], error) {
+	iterator := iterate(c, func(params *map[string]string) (_ v2.Listable[v2.Zone], err error) {
 		if params != nil && name != "" {
 			(*params)["filter"] = name
 		}
 
+		ctx, span := startSpan(ctx, c.tracer, "ListZonesPage", offsetKey.String((*params)["offset"]))
+		defer func() { endSpan(span, err) }()
+
 		return c.dns.ListZones(ctx, params)
 	})
 
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// diff shows changes between two snapshot files or between a snapshot file and the live zone state.
+func diff(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format: text, unified or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		return errors.New("one or two snapshot files are required")
+	}
+
+	before, err := readSnapshot(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	zoneDiff := selectel.ZoneDiff{
+		Before:      before.RRSets,
+		BeforeLabel: flags.Arg(0),
+	}
+
+	if flags.NArg() == 2 {
+		after, err := readSnapshot(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+
+		if after.Zone != before.Zone {
+			return errors.Errorf("snapshots belong to different zones: %s and %s", before.Zone, after.Zone)
+		}
+
+		zoneDiff.After = after.RRSets
+		zoneDiff.AfterLabel = flags.Arg(1)
+	} else {
+		client, err := newClient()
+		if err != nil {
+			return err
+		}
+
+		zoneDiff.After, err = client.GetRRSets(ctx, before.Zone)
+		if err != nil {
+			return errors.Wrap(err, "get RR sets")
+		}
+
+		zoneDiff.AfterLabel = before.Zone
+	}
+
+	switch *format {
+	case "text":
+		return zoneDiff.WriteText(stdout)
+	case "unified":
+		return zoneDiff.WriteUnified(stdout)
+	case "json":
+		return zoneDiff.WriteJSON(stdout)
+	default:
+		return errors.Errorf("unknown format %s", *format)
+	}
+}
+
+func readSnapshot(path string) (*selectel.Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open snapshot file")
+	}
+
+	defer file.Close()
+	snapshot, err := selectel.ReadSnapshot(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read snapshot %s", path)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// export writes zone records to a BIND zone file.
+func export(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	disabled := flags.Bool("disabled", false, "include disabled records as comments")
+	output := flags.String("o", "-", "output file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("zone name is required")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	zone := flags.Arg(0)
+	sets, err := client.GetRRSets(ctx, zone)
+	if err != nil {
+		return errors.Wrap(err, "get RR sets")
+	}
+
+	w := stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return errors.Wrap(err, "create output file")
+		}
+
+		defer func() {
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+		}()
+
+		w = file
+	}
+
+	return selectel.ExportZone(w, zone, sets, selectel.ExportOptions{IncludeDisabled: *disabled})
+}
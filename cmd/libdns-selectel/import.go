@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// importZone shows changes needed to make the zone match a BIND zone file and optionally applies them.
+func importZone(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	input := flags.String("f", "-", "input zone file")
+	apply := flags.Bool("apply", false, "apply changes instead of showing them only")
+	var opts selectel.ImportOptions
+	flags.BoolVar(&opts.AllowInclude, "include", false, "allow $INCLUDE directives")
+	flags.BoolVar(&opts.IncludeSOA, "soa", false, "import SOA record")
+	flags.BoolVar(&opts.IncludeApexNS, "ns", false, "import NS records at zone apex")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("zone name is required")
+	}
+
+	r := stdin
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return errors.Wrap(err, "open input file")
+		}
+
+		defer file.Close()
+		r = file
+		opts.Filename = *input
+	}
+
+	zone := flags.Arg(0)
+	next, err := selectel.ImportZone(r, zone, opts)
+	if err != nil {
+		return errors.Wrap(err, "import zone")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	prev, err := client.GetRRSets(ctx, zone)
+	if err != nil {
+		return errors.Wrap(err, "get RR sets")
+	}
+
+	plan := selectel.Diff(opts.Skip(prev), next)
+	if err := plan.Format(stdout); err != nil {
+		return err
+	}
+
+	if len(plan) == 0 || !*apply {
+		return nil
+	}
+
+	return plan.Apply(ctx, client, zone)
+}
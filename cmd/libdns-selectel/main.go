@@ -0,0 +1,116 @@
+// Command libdns-selectel manages Selectel DNS zones from the command line.
+//
+// Credentials are read from USERNAME, PASSWORD, ACCOUNT_ID and PROJECT_NAME
+// environment variables (see .env.template).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"zones":    {usage: "zones [-format table|json]", run: zones},
+	"get":      {usage: "get [-format table|json] zone", run: get},
+	"append":   {usage: "append [-format table|json] [-name name -type type [-ttl ttl] -data data...] zone", run: modify("append", appendRecords)},
+	"set":      {usage: "set [-format table|json] [-name name -type type [-ttl ttl] -data data...] zone", run: modify("set", setRecords)},
+	"delete":   {usage: "delete [-format table|json] [-name name [-type type] [-ttl ttl] [-data data...]] zone", run: modify("delete", deleteRecords)},
+	"export":   {usage: "export [-disabled] [-o file] zone", run: export},
+	"import":   {usage: "import [-apply] [-include] [-soa] [-ns] [-f file] zone", run: importZone},
+	"sync":     {usage: "sync [-apply] [-prune] [-ignore name[:type]...] [-f file]", run: syncZone},
+	"snapshot": {usage: "snapshot [-o file] zone", run: snapshot},
+	"restore":  {usage: "restore [-apply] [-f file]", run: restore},
+	"diff":     {usage: "diff [-format text|unified|json] snapshot [snapshot]", run: diff},
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := cmd.run(ctx, flag.Args()[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	for _, name := range slices.Sorted(maps.Keys(commands)) {
+		fmt.Fprintf(os.Stderr, "  libdns-selectel %s\n", commands[name].usage)
+	}
+
+	fmt.Fprintln(os.Stderr, `
+Records for append, set and delete are read from stdin unless -name is specified,
+either as a JSON array or as lines of "name ttl type data".`)
+}
+
+// newClient creates a client using credentials from the environment.
+// SELECTEL_AUTH_URL and SELECTEL_API_URL override API endpoints.
+func newClient() (selectel.Client, error) {
+	var creds selectel.Credentials
+	if err := env.ParseWithOptions(&creds, env.Options{UseFieldNameByDefault: true}); err != nil {
+		return nil, errors.Wrap(err, "parse credentials")
+	}
+
+	if err := creds.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validate credentials")
+	}
+
+	var endpoints struct {
+		AuthURL string `env:"AUTH_URL"`
+		APIURL  string `env:"API_URL"`
+	}
+
+	if err := env.ParseWithOptions(&endpoints, env.Options{Prefix: "SELECTEL_"}); err != nil {
+		return nil, errors.Wrap(err, "parse endpoints")
+	}
+
+	var clientOpts []selectel.Option
+	if endpoints.AuthURL != "" {
+		clientOpts = append(clientOpts, selectel.WithAuthURL(endpoints.AuthURL))
+	}
+
+	if endpoints.APIURL != "" {
+		clientOpts = append(clientOpts, selectel.WithAPIURL(endpoints.APIURL))
+	}
+
+	return selectel.NewClient(creds, clientOpts...), nil
+}
+
+func newProvider() (*selectel.Provider, error) {
+	client, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return selectel.NewProvider(client), nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/selectel/domains-go/pkg/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+var testCreds = selectel.Credentials{
+	Username:    "user",
+	Password:    "s3cr3t",
+	AccountID:   "123456",
+	ProjectName: "project",
+}
+
+// setupServer starts a stand-in server with zone1.org. and points the command environment to it.
+func setupServer(t *testing.T) *selecteltest.Server {
+	server := selecteltest.NewServer(testCreds)
+	t.Cleanup(server.Close)
+	server.AddZone("zone1.org.")
+
+	t.Setenv("USERNAME", testCreds.Username)
+	t.Setenv("PASSWORD", testCreds.Password)
+	t.Setenv("ACCOUNT_ID", testCreds.AccountID)
+	t.Setenv("PROJECT_NAME", testCreds.ProjectName)
+	t.Setenv("SELECTEL_AUTH_URL", server.AuthURL())
+	t.Setenv("SELECTEL_API_URL", server.APIURL())
+
+	client, err := newClient()
+	require.NoError(t, err)
+	require.NoError(t, client.CreateRRSet(context.Background(), "zone1.org.", &selectel.RRSet{
+		Key: selectel.RRSetKey{Name: "www", Type: "A"},
+		TTL: time.Hour,
+		RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), selectel.SetOf("2.2.2.2")},
+	}))
+
+	return server
+}
+
+func TestExport(t *testing.T) {
+	setupServer(t)
+	output := filepath.Join(t.TempDir(), "zone1.org.zone")
+	require.NoError(t, export(context.Background(), []string{"-disabled", "-o", output, "zone1.org."}, nil, io.Discard))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "$ORIGIN zone1.org.\n"+
+		"$TTL 3600\n"+
+		"www\t3600\tIN\tA\t1.1.1.1\n"+
+		"; disabled: www\t3600\tIN\tA\t2.2.2.2\n", string(data))
+
+	assert.EqualError(t, export(context.Background(), nil, nil, io.Discard), "zone name is required")
+}
+
+func TestImport(t *testing.T) {
+	server := setupServer(t)
+	input := filepath.Join(t.TempDir(), "zone1.org.zone")
+	require.NoError(t, os.WriteFile(input, []byte(`$TTL 1h
+@	IN	NS	ns1.selectel.org.
+www	IN	A	1.1.1.1
+	IN	A	3.3.3.3
+`), 0o600))
+
+	var stdout bytes.Buffer
+	require.NoError(t, importZone(context.Background(), []string{"-f", input, "zone1.org."}, nil, &stdout))
+	assert.Equal(t, "+ www\t3600\tIN\tA\t3.3.3.3\n", stdout.String())
+	assert.Equal(t, []v2.RecordItem{{Content: "1.1.1.1"}, {Content: "2.2.2.2", Disabled: true}}, server.RRSets("zone1.org.")[0].Records)
+
+	stdout.Reset()
+	require.NoError(t, importZone(context.Background(), []string{"-f", input, "-apply", "zone1.org."}, nil, &stdout))
+	assert.Equal(t, "+ www\t3600\tIN\tA\t3.3.3.3\n", stdout.String())
+	assert.Equal(t, []v2.RecordItem{{Content: "1.1.1.1"}, {Content: "2.2.2.2", Disabled: true}, {Content: "3.3.3.3"}}, server.RRSets("zone1.org.")[0].Records)
+
+	stdout.Reset()
+	require.NoError(t, importZone(context.Background(), []string{"-f", input, "-apply", "zone1.org."}, nil, &stdout))
+	assert.Empty(t, stdout.String())
+}
+
+func TestRecords(t *testing.T) {
+	setupServer(t)
+	ctx := context.Background()
+
+	var stdout bytes.Buffer
+	require.NoError(t, zones(ctx, nil, nil, &stdout))
+	assert.Equal(t, "zone1.org.\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, modify("append", appendRecords)(ctx,
+		[]string{"-name", "mail", "-type", "MX", "-ttl", "1h", "-data", "10 mx1.zone1.org.", "-data", "20 mx2.zone1.org.", "zone1.org."},
+		nil, &stdout))
+	assert.Equal(t, ""+
+		"NAME  TTL   TYPE  DATA\n"+
+		"mail  3600  MX    10 mx1.zone1.org.\n"+
+		"mail  3600  MX    20 mx2.zone1.org.\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, modify("set", setRecords)(ctx, []string{"-format", "json", "zone1.org."},
+		strings.NewReader("# comment\nwww\t300 A 3.3.3.3\ntxt 1m TXT hello world\n"), &stdout))
+	assert.JSONEq(t, `[
+		{"name": "txt", "type": "TXT", "ttl": 60, "data": "hello world"},
+		{"name": "www", "type": "A", "ttl": 300, "data": "3.3.3.3"}
+	]`, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, modify("delete", deleteRecords)(ctx, []string{"-format", "json", "zone1.org."},
+		strings.NewReader(`[{"name": "mail", "type": "MX", "data": "20 mx2.zone1.org."}, {"name": "txt"}]`), &stdout))
+	assert.JSONEq(t, `[
+		{"name": "mail", "type": "MX", "ttl": 3600, "data": "20 mx2.zone1.org."},
+		{"name": "txt", "type": "TXT", "ttl": 60, "data": "hello world"}
+	]`, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, get(ctx, []string{"-format", "json", "zone1.org."}, nil, &stdout))
+	assert.JSONEq(t, `[
+		{"name": "mail", "type": "MX", "ttl": 3600, "data": "10 mx1.zone1.org."},
+		{"name": "www", "type": "A", "ttl": 300, "data": "3.3.3.3"}
+	]`, stdout.String())
+
+	assert.EqualError(t, modify("append", appendRecords)(ctx, []string{"zone1.org."}, strings.NewReader(""), io.Discard), "no records specified")
+	assert.EqualError(t, modify("append", appendRecords)(ctx, []string{"zone1.org."}, strings.NewReader("www 1x A"), io.Discard),
+		`read records: line 1: parse ttl: time: unknown unit "x" in duration "1x"`)
+}
+
+func TestSync(t *testing.T) {
+	server := setupServer(t)
+	ctx := context.Background()
+	state := `
+zone: zone1.org.
+rrsets:
+  - name: mail
+    type: MX
+    ttl: 3600
+    values: ["10 mx.zone1.org."]
+`
+
+	var stdout bytes.Buffer
+	require.NoError(t, syncZone(ctx, []string{"-apply"}, strings.NewReader(state), &stdout))
+	assert.Equal(t, "+ mail\t3600\tIN\tMX\t10 mx.zone1.org.\n", stdout.String())
+	assert.Len(t, server.RRSets("zone1.org."), 2)
+
+	stdout.Reset()
+	require.NoError(t, syncZone(ctx, []string{"-prune", "-ignore", "w*:A"}, strings.NewReader(state), &stdout))
+	assert.Empty(t, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, syncZone(ctx, []string{"-prune", "-apply"}, strings.NewReader(state), &stdout))
+	assert.Equal(t, "- www\t3600\tIN\tA\t1.1.1.1\n", stdout.String())
+	assert.Len(t, server.RRSets("zone1.org."), 1)
+}
+
+func TestSnapshot(t *testing.T) {
+	server := setupServer(t)
+	ctx := context.Background()
+	output := filepath.Join(t.TempDir(), "zone1.org.json")
+	require.NoError(t, snapshot(ctx, []string{"-o", output, "zone1.org."}, nil, io.Discard))
+
+	require.NoError(t, modify("set", setRecords)(ctx, []string{"zone1.org."}, strings.NewReader("www 1h A 2.2.2.2\nmail 1h A 3.3.3.3\n"), io.Discard))
+
+	var stdout bytes.Buffer
+	require.NoError(t, restore(ctx, []string{"-f", output, "-apply"}, nil, &stdout))
+	assert.Equal(t, ""+
+		"- mail\t3600\tIN\tA\t3.3.3.3\n"+
+		"- www\t3600\tIN\tA\t2.2.2.2\n"+
+		"+ www\t3600\tIN\tA\t1.1.1.1\n"+
+		"+ ; disabled: www\t3600\tIN\tA\t2.2.2.2\n", stdout.String())
+	require.Len(t, server.RRSets("zone1.org."), 1)
+	assert.Equal(t, []v2.RecordItem{{Content: "1.1.1.1"}, {Content: "2.2.2.2", Disabled: true}}, server.RRSets("zone1.org.")[0].Records)
+
+	stdout.Reset()
+	require.NoError(t, restore(ctx, []string{"-f", output}, nil, &stdout))
+	assert.Empty(t, stdout.String())
+}
+
+func TestDiff(t *testing.T) {
+	setupServer(t)
+	ctx := context.Background()
+	before := filepath.Join(t.TempDir(), "before.json")
+	require.NoError(t, snapshot(ctx, []string{"-o", before, "zone1.org."}, nil, io.Discard))
+
+	require.NoError(t, modify("set", setRecords)(ctx, []string{"zone1.org."}, strings.NewReader("www 5m A 1.1.1.1\n"), io.Discard))
+
+	var stdout bytes.Buffer
+	require.NoError(t, diff(ctx, []string{before}, nil, &stdout))
+	assert.Equal(t, "update A www\n  ttl 3600 -> 300\n", stdout.String())
+
+	after := filepath.Join(t.TempDir(), "after.json")
+	require.NoError(t, snapshot(ctx, []string{"-o", after, "zone1.org."}, nil, io.Discard))
+
+	stdout.Reset()
+	require.NoError(t, diff(ctx, []string{"-format", "json", before, after}, nil, &stdout))
+	assert.JSONEq(t, `[{"op": "update", "name": "www", "type": "A", "ttl_before": 3600, "ttl_after": 300}]`, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, diff(ctx, []string{"-format", "unified", before, after}, nil, &stdout))
+	assert.Equal(t, "--- "+before+"\n+++ "+after+"\n"+
+		"@@ -1,2 +1,2 @@\n"+
+		"-www\t3600\tIN\tA\t1.1.1.1\n"+
+		"+www\t300\tIN\tA\t1.1.1.1\n"+
+		"-; disabled: www\t3600\tIN\tA\t2.2.2.2\n"+
+		"+; disabled: www\t300\tIN\tA\t2.2.2.2\n", stdout.String())
+
+	assert.EqualError(t, diff(ctx, nil, nil, io.Discard), "one or two snapshot files are required")
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// record is a JSON representation of libdns.RR with TTL in seconds.
+type record struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	TTL  int    `json:"ttl,omitempty"`
+	Data string `json:"data,omitempty"`
+}
+
+func (r record) RR() libdns.RR {
+	return libdns.RR{
+		Name: r.Name,
+		Type: r.Type,
+		TTL:  time.Duration(r.TTL) * time.Second,
+		Data: r.Data,
+	}
+}
+
+// formatFlag registers -format flag for printing results.
+func formatFlag(flags *flag.FlagSet) *string {
+	return flags.String("format", "table", "output format: table or json")
+}
+
+func zones(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("zones", flag.ContinueOnError)
+	format := formatFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	provider, err := newProvider()
+	if err != nil {
+		return err
+	}
+
+	zones, err := provider.ListZones(ctx)
+	if err != nil {
+		return errors.Wrap(err, "list zones")
+	}
+
+	switch *format {
+	case "json":
+		names := make([]string, len(zones))
+		for i, zone := range zones {
+			names[i] = zone.Name
+		}
+
+		return writeJSON(stdout, names)
+
+	case "table":
+		for _, zone := range zones {
+			if _, err := fmt.Fprintln(stdout, zone.Name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		return errors.Errorf("unknown format %s", *format)
+	}
+}
+
+func get(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	format := formatFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("zone name is required")
+	}
+
+	provider, err := newProvider()
+	if err != nil {
+		return err
+	}
+
+	records, err := provider.GetRecords(ctx, flags.Arg(0))
+	if err != nil {
+		return errors.Wrap(err, "get records")
+	}
+
+	return writeRecords(stdout, *format, records)
+}
+
+type modifyFunc func(ctx context.Context, provider *selectel.Provider, zone string, records []libdns.Record) ([]libdns.Record, error)
+
+func appendRecords(ctx context.Context, provider *selectel.Provider, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	return provider.AppendRecords(ctx, zone, records)
+}
+
+func setRecords(ctx context.Context, provider *selectel.Provider, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	return provider.SetRecords(ctx, zone, records)
+}
+
+func deleteRecords(ctx context.Context, provider *selectel.Provider, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	return provider.DeleteRecords(ctx, zone, records)
+}
+
+// modify creates a command which reads records from flags or stdin, passes them to fn and prints the result.
+func modify(name string, fn modifyFunc) func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	return func(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		format := formatFlag(flags)
+		recordName := flags.String("name", "", "record name relative to the zone (@ for apex)")
+		typ := flags.String("type", "", "record type")
+		ttl := flags.Duration("ttl", 0, "record TTL")
+		var data stringsFlag
+		flags.Var(&data, "data", "record data (may be repeated)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		if flags.NArg() != 1 {
+			return errors.New("zone name is required")
+		}
+
+		var records []libdns.Record
+		if *recordName != "" {
+			if len(data) == 0 {
+				data = append(data, "")
+			}
+
+			for _, data := range data {
+				records = append(records, libdns.RR{Name: *recordName, Type: *typ, TTL: *ttl, Data: data})
+			}
+		} else {
+			var err error
+			records, err = readRecords(stdin)
+			if err != nil {
+				return errors.Wrap(err, "read records")
+			}
+		}
+
+		if len(records) == 0 {
+			return errors.New("no records specified")
+		}
+
+		provider, err := newProvider()
+		if err != nil {
+			return err
+		}
+
+		records, err = fn(ctx, provider, flags.Arg(0), records)
+		if werr := writeRecords(stdout, *format, records); err == nil {
+			err = werr
+		}
+
+		return err
+	}
+}
+
+// readRecords reads records either as a JSON array or as lines of "name ttl type data".
+// TTL may be specified in seconds or as a duration.
+func readRecords(r io.Reader) ([]libdns.Record, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(input), []byte("[")) {
+		var values []record
+		if err := json.Unmarshal(input, &values); err != nil {
+			return nil, errors.Wrap(err, "unmarshal records")
+		}
+
+		records := make([]libdns.Record, len(values))
+		for i, value := range values {
+			records[i] = value
+		}
+
+		return records, nil
+	}
+
+	var records []libdns.Record
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var fields [3]string
+		for i := range fields {
+			text = strings.TrimLeft(text, " \t")
+			end := strings.IndexAny(text, " \t")
+			if end < 0 {
+				end = len(text)
+			}
+
+			fields[i], text = text[:end], text[end:]
+		}
+
+		if fields[2] == "" {
+			return nil, errors.Errorf("line %d: expected name, ttl, type and data", line)
+		}
+
+		ttl, err := parseTTL(fields[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: parse ttl", line)
+		}
+
+		rr := libdns.RR{Name: fields[0], TTL: ttl, Type: fields[2], Data: strings.TrimSpace(text)}
+		records = append(records, rr)
+	}
+
+	return records, scanner.Err()
+}
+
+func parseTTL(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	return time.ParseDuration(value)
+}
+
+func writeRecords(w io.Writer, format string, records []libdns.Record) error {
+	switch format {
+	case "json":
+		values := make([]record, len(records))
+		for i, r := range records {
+			rr := r.RR()
+			values[i] = record{Name: rr.Name, Type: rr.Type, TTL: int(rr.TTL.Seconds()), Data: rr.Data}
+		}
+
+		return writeJSON(w, values)
+
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NAME\tTTL\tTYPE\tDATA")
+		for _, r := range records {
+			rr := r.RR()
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", rr.Name, int(rr.TTL.Seconds()), rr.Type, rr.Data)
+		}
+
+		return tw.Flush()
+
+	default:
+		return errors.Errorf("unknown format %s", format)
+	}
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// stringsFlag collects values of a repeated flag.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// snapshot writes the full zone state to a snapshot file.
+func snapshot(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) (err error) {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	output := flags.String("o", "-", "output file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New("zone name is required")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := selectel.TakeSnapshot(ctx, client, flags.Arg(0))
+	if err != nil {
+		return errors.Wrap(err, "take snapshot")
+	}
+
+	w := stdout
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return errors.Wrap(err, "create output file")
+		}
+
+		defer func() {
+			if cerr := file.Close(); err == nil {
+				err = cerr
+			}
+		}()
+
+		w = file
+	}
+
+	return snapshot.Write(w)
+}
+
+// restore shows changes needed to restore a snapshot file and optionally applies them.
+func restore(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	input := flags.String("f", "-", "input snapshot file")
+	apply := flags.Bool("apply", false, "apply changes instead of showing them only")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := stdin
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return errors.Wrap(err, "open input file")
+		}
+
+		defer file.Close()
+		r = file
+	}
+
+	snapshot, err := selectel.ReadSnapshot(r)
+	if err != nil {
+		return errors.Wrap(err, "read snapshot")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	current, err := client.GetRRSets(ctx, snapshot.Zone)
+	if err != nil {
+		return errors.Wrap(err, "get RR sets")
+	}
+
+	plan := snapshot.Plan(current)
+	if err := plan.Format(stdout); err != nil {
+		return err
+	}
+
+	if len(plan) == 0 || !*apply {
+		return nil
+	}
+
+	return plan.Apply(ctx, client, snapshot.Zone)
+}
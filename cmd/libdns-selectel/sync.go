@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// syncZone shows changes needed to make a zone match a desired-state file and optionally applies them.
+func syncZone(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	input := flags.String("f", "-", "desired state file (YAML or JSON)")
+	apply := flags.Bool("apply", false, "apply changes instead of showing them only")
+	var opts selectel.SyncOptions
+	flags.BoolVar(&opts.Prune, "prune", false, "delete RR sets missing from the desired state")
+	flags.Func("ignore", "ignore RR sets matching name[:type] glob pattern (may be repeated)", func(value string) error {
+		name, typ, _ := strings.Cut(value, ":")
+		opts.Ignore = append(opts.Ignore, selectel.IgnoreRule{Name: name, Type: typ})
+		return nil
+	})
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	r := stdin
+	if *input != "-" {
+		file, err := os.Open(*input)
+		if err != nil {
+			return errors.Wrap(err, "open input file")
+		}
+
+		defer file.Close()
+		r = file
+	}
+
+	state, err := selectel.ReadDesiredState(r)
+	if err != nil {
+		return errors.Wrap(err, "read desired state")
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	current, err := client.GetRRSets(ctx, state.Zone)
+	if err != nil {
+		return errors.Wrap(err, "get RR sets")
+	}
+
+	plan, err := state.Plan(current, opts)
+	if err != nil {
+		return errors.Wrap(err, "plan")
+	}
+
+	if err := plan.Format(stdout); err != nil {
+		return err
+	}
+
+	if len(plan) == 0 || !*apply {
+		return nil
+	}
+
+	return plan.Apply(ctx, client, state.Zone)
+}
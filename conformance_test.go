@@ -0,0 +1,18 @@
+package selectel_test
+
+import (
+	"testing"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Conformance(t *testing.T) {
+	selecteltest.RunConformance(t, func(t *testing.T) (selecteltest.Provider, string) {
+		server := selecteltest.NewServer(testCreds)
+		t.Cleanup(server.Close)
+
+		server.AddZone("zone1.org.")
+		return selectel.NewProvider(selectel.NewClient(testCreds, server.Options()...)), "zone1.org."
+	})
+}
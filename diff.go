@@ -0,0 +1,291 @@
+package selectel
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ZoneDiff compares two states of a zone, e.g. snapshots or RR sets returned by Client.GetRRSets.
+type ZoneDiff struct {
+	Before map[RRSetKey]*RRSet
+	After  map[RRSetKey]*RRSet
+
+	// BeforeLabel and AfterLabel are used in unified diff headers.
+	BeforeLabel string
+	AfterLabel  string
+}
+
+// RRSetDiff describes changes of an RR set between two zone states.
+type RRSetDiff struct {
+	Op   ChangeOp `json:"op"`
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+
+	// TTLBefore and TTLAfter are TTLs in seconds, zero for missing RR sets.
+	TTLBefore int `json:"ttl_before,omitempty"`
+	TTLAfter  int `json:"ttl_after,omitempty"`
+
+	// Added and Removed list enabled records which appeared or disappeared.
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Enabled and Disabled list records which changed their state.
+	Enabled  []string `json:"enabled,omitempty"`
+	Disabled []string `json:"disabled,omitempty"`
+	// AddedDisabled and RemovedDisabled list disabled records which appeared or disappeared.
+	AddedDisabled   []string `json:"added_disabled,omitempty"`
+	RemovedDisabled []string `json:"removed_disabled,omitempty"`
+}
+
+// Changes returns differences of added, removed and changed RR sets ordered by RR set key.
+func (d ZoneDiff) Changes() []RRSetDiff {
+	plan := DiffAll(d.Before, d.After)
+	diffs := make([]RRSetDiff, len(plan))
+	for i, change := range plan {
+		diffs[i] = change.diff()
+	}
+
+	return diffs
+}
+
+func (c PlanChange) diff() RRSetDiff {
+	diff := RRSetDiff{Op: c.Op, Name: c.Key.Name, Type: c.Key.Type}
+	var before, after RRs
+	if c.Before != nil {
+		diff.TTLBefore = int(c.Before.TTL.Seconds())
+		before = c.Before.RRs
+	}
+
+	if c.After != nil {
+		diff.TTLAfter = int(c.After.TTL.Seconds())
+		after = c.After.RRs
+	}
+
+	data := make(Set[string])
+	for _, set := range slices.Concat(before[:], after[:]) {
+		maps.Copy(data, set)
+	}
+
+	for _, data := range sorted(data) {
+		switch {
+		case before[enabled][data] && after[disabled][data]:
+			diff.Disabled = append(diff.Disabled, data)
+		case before[disabled][data] && after[enabled][data]:
+			diff.Enabled = append(diff.Enabled, data)
+		case before[enabled][data] && !after[enabled][data]:
+			diff.Removed = append(diff.Removed, data)
+		case before[disabled][data] && !after[disabled][data]:
+			diff.RemovedDisabled = append(diff.RemovedDisabled, data)
+		case after[enabled][data] && !before[enabled][data]:
+			diff.Added = append(diff.Added, data)
+		case after[disabled][data] && !before[disabled][data]:
+			diff.AddedDisabled = append(diff.AddedDisabled, data)
+		}
+	}
+
+	return diff
+}
+
+// WriteText writes a human-readable description of the changes:
+//
+//	update A www
+//	  ttl 3600 -> 300
+//	  + 3.3.3.3
+//	  - 4.4.4.4
+//	  ~ 2.2.2.2 disabled -> enabled
+func (d ZoneDiff) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, diff := range d.Changes() {
+		_, _ = fmt.Fprintf(bw, "%s %s %s\n", diff.Op, diff.Type, diff.Name)
+		switch {
+		case diff.Op == ChangeCreate:
+			_, _ = fmt.Fprintf(bw, "  ttl %d\n", diff.TTLAfter)
+		case diff.Op == ChangeDelete:
+			_, _ = fmt.Fprintf(bw, "  ttl %d\n", diff.TTLBefore)
+		case diff.TTLBefore != diff.TTLAfter:
+			_, _ = fmt.Fprintf(bw, "  ttl %d -> %d\n", diff.TTLBefore, diff.TTLAfter)
+		}
+
+		for _, group := range []struct {
+			format string
+			values []string
+		}{
+			{"  + %s\n", diff.Added},
+			{"  + %s (disabled)\n", diff.AddedDisabled},
+			{"  - %s\n", diff.Removed},
+			{"  - %s (disabled)\n", diff.RemovedDisabled},
+			{"  ~ %s disabled -> enabled\n", diff.Enabled},
+			{"  ~ %s enabled -> disabled\n", diff.Disabled},
+		} {
+			for _, value := range group.values {
+				_, _ = fmt.Fprintf(bw, group.format, value)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// WriteJSON writes the changes as a JSON array of RRSetDiff.
+func (d ZoneDiff) WriteJSON(w io.Writer) error {
+	diffs := d.Changes()
+	if diffs == nil {
+		diffs = []RRSetDiff{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(diffs)
+}
+
+// diffContext is the number of unchanged lines surrounding changes in unified diff hunks.
+const diffContext = 3
+
+type diffLine struct {
+	key   RRSetKey
+	state int
+	data  string
+	text  string
+}
+
+func (l diffLine) compare(other diffLine) int {
+	return cmp.Or(l.key.Compare(other.key), cmp.Compare(l.state, other.state), strings.Compare(l.data, other.data))
+}
+
+func zoneLines(sets map[RRSetKey]*RRSet) []diffLine {
+	var lines []diffLine
+	for _, key := range sortedKeys(sets) {
+		set := sets[key]
+		for _, state := range []int{enabled, disabled} {
+			for _, data := range sorted(set.RRs[state]) {
+				text := formatZoneLine(set, data)
+				if state == disabled {
+					text = "; disabled: " + text
+				}
+
+				lines = append(lines, diffLine{key: key, state: state, data: data, text: text})
+			}
+		}
+	}
+
+	return lines
+}
+
+type diffOp struct {
+	kind byte
+	text string
+}
+
+// WriteUnified writes the changes as a unified diff of zone file lines,
+// with disabled records written as "; disabled:" comments.
+func (d ZoneDiff) WriteUnified(w io.Writer) error {
+	before, after := zoneLines(d.Before), zoneLines(d.After)
+	var ops []diffOp
+	for len(before) > 0 || len(after) > 0 {
+		var c int
+		switch {
+		case len(before) == 0:
+			c = 1
+		case len(after) == 0:
+			c = -1
+		default:
+			c = before[0].compare(after[0])
+		}
+
+		switch {
+		case c == 0 && before[0].text == after[0].text:
+			ops = append(ops, diffOp{' ', before[0].text})
+			before, after = before[1:], after[1:]
+		case c == 0:
+			ops = append(ops, diffOp{'-', before[0].text}, diffOp{'+', after[0].text})
+			before, after = before[1:], after[1:]
+		case c < 0:
+			ops = append(ops, diffOp{'-', before[0].text})
+			before = before[1:]
+		default:
+			ops = append(ops, diffOp{'+', after[0].text})
+			after = after[1:]
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	headerWritten := false
+	var beforeLine, afterLine int
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			beforeLine++
+			afterLine++
+			i++
+			continue
+		}
+
+		start := max(0, i-diffContext)
+		end := i
+		for unchanged := 0; end < len(ops) && unchanged <= 2*diffContext; end++ {
+			if ops[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		for end > i && ops[end-1].kind == ' ' {
+			end--
+		}
+
+		end = min(len(ops), end+diffContext)
+
+		beforeStart, afterStart := beforeLine-(i-start), afterLine-(i-start)
+		var beforeCount, afterCount int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				beforeCount++
+			}
+
+			if op.kind != '-' {
+				afterCount++
+			}
+		}
+
+		if !headerWritten {
+			_, _ = fmt.Fprintf(bw, "--- %s\n+++ %s\n", cmp.Or(d.BeforeLabel, "before"), cmp.Or(d.AfterLabel, "after"))
+			headerWritten = true
+		}
+
+		_, _ = fmt.Fprintf(bw, "@@ -%s +%s @@\n", hunkRange(beforeStart, beforeCount), hunkRange(afterStart, afterCount))
+		for _, op := range ops[start:end] {
+			_, _ = fmt.Fprintf(bw, "%c%s\n", op.kind, op.text)
+		}
+
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				beforeLine++
+			}
+
+			if op.kind != '-' {
+				afterLine++
+			}
+		}
+
+		i = end
+	}
+
+	return bw.Flush()
+}
+
+// hunkRange formats a hunk range from a zero-based start line and a line count.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
@@ -0,0 +1,136 @@
+package selectel
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZoneDiff(t *testing.T) {
+	before := map[RRSetKey]*RRSet{
+		{Name: "mail", Type: "A"}: {
+			Key: RRSetKey{Name: "mail", Type: "A"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1"), nil},
+		},
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1", "2.2.2.2"), SetOf("3.3.3.3", "4.4.4.4")},
+		},
+	}
+
+	after := map[RRSetKey]*RRSet{
+		{Name: "@", Type: "TXT"}: {
+			Key: RRSetKey{Name: "@", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: RRs{SetOf("hello"), SetOf("world")},
+		},
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			TTL: 5 * time.Minute,
+			RRs: RRs{SetOf("1.1.1.1", "3.3.3.3", "5.5.5.5"), SetOf("2.2.2.2")},
+		},
+	}
+
+	diff := ZoneDiff{Before: before, After: after, BeforeLabel: "old.json", AfterLabel: "new.json"}
+	assert.Equal(t, []RRSetDiff{
+		{Op: ChangeCreate, Name: "@", Type: "TXT", TTLAfter: 60, Added: []string{"hello"}, AddedDisabled: []string{"world"}},
+		{Op: ChangeDelete, Name: "mail", Type: "A", TTLBefore: 3600, Removed: []string{"1.1.1.1"}},
+		{
+			Op:              ChangeUpdate,
+			Name:            "www",
+			Type:            "A",
+			TTLBefore:       3600,
+			TTLAfter:        300,
+			Added:           []string{"5.5.5.5"},
+			Enabled:         []string{"3.3.3.3"},
+			Disabled:        []string{"2.2.2.2"},
+			RemovedDisabled: []string{"4.4.4.4"},
+		},
+	}, diff.Changes())
+
+	var text strings.Builder
+	require.NoError(t, diff.WriteText(&text))
+	assert.Equal(t, ""+
+		"create TXT @\n"+
+		"  ttl 60\n"+
+		"  + hello\n"+
+		"  + world (disabled)\n"+
+		"delete A mail\n"+
+		"  ttl 3600\n"+
+		"  - 1.1.1.1\n"+
+		"update A www\n"+
+		"  ttl 3600 -> 300\n"+
+		"  + 5.5.5.5\n"+
+		"  - 4.4.4.4 (disabled)\n"+
+		"  ~ 3.3.3.3 disabled -> enabled\n"+
+		"  ~ 2.2.2.2 enabled -> disabled\n", text.String())
+
+	var unified strings.Builder
+	require.NoError(t, diff.WriteUnified(&unified))
+	assert.Equal(t, ""+
+		"--- old.json\n"+
+		"+++ new.json\n"+
+		"@@ -1,5 +1,6 @@\n"+
+		"+@\t60\tIN\tTXT\t\"hello\"\n"+
+		"+; disabled: @\t60\tIN\tTXT\t\"world\"\n"+
+		"-mail\t3600\tIN\tA\t1.1.1.1\n"+
+		"-www\t3600\tIN\tA\t1.1.1.1\n"+
+		"+www\t300\tIN\tA\t1.1.1.1\n"+
+		"-www\t3600\tIN\tA\t2.2.2.2\n"+
+		"+www\t300\tIN\tA\t3.3.3.3\n"+
+		"+www\t300\tIN\tA\t5.5.5.5\n"+
+		"+; disabled: www\t300\tIN\tA\t2.2.2.2\n"+
+		"-; disabled: www\t3600\tIN\tA\t3.3.3.3\n"+
+		"-; disabled: www\t3600\tIN\tA\t4.4.4.4\n", unified.String())
+
+	var js strings.Builder
+	require.NoError(t, ZoneDiff{}.WriteJSON(&js))
+	assert.JSONEq(t, `[]`, js.String())
+}
+
+func TestZoneDiff_Hunks(t *testing.T) {
+	before := make(map[RRSetKey]*RRSet)
+	for i := range 20 {
+		key := RRSetKey{Name: fmt.Sprintf("host%02d", i), Type: "A"}
+		before[key] = &RRSet{Key: key, TTL: time.Hour, RRs: RRs{SetOf(fmt.Sprintf("10.0.0.%d", i)), nil}}
+	}
+
+	after := make(map[RRSetKey]*RRSet)
+	for key, set := range before {
+		after[key] = set
+	}
+
+	for _, i := range []int{2, 15} {
+		key := RRSetKey{Name: fmt.Sprintf("host%02d", i), Type: "A"}
+		after[key] = &RRSet{Key: key, TTL: time.Hour, RRs: RRs{SetOf("10.0.1.1"), nil}}
+	}
+
+	var unified strings.Builder
+	require.NoError(t, ZoneDiff{Before: before, After: after}.WriteUnified(&unified))
+	assert.Equal(t, ""+
+		"--- before\n"+
+		"+++ after\n"+
+		"@@ -1,6 +1,6 @@\n"+
+		" host00\t3600\tIN\tA\t10.0.0.0\n"+
+		" host01\t3600\tIN\tA\t10.0.0.1\n"+
+		"-host02\t3600\tIN\tA\t10.0.0.2\n"+
+		"+host02\t3600\tIN\tA\t10.0.1.1\n"+
+		" host03\t3600\tIN\tA\t10.0.0.3\n"+
+		" host04\t3600\tIN\tA\t10.0.0.4\n"+
+		" host05\t3600\tIN\tA\t10.0.0.5\n"+
+		"@@ -13,7 +13,7 @@\n"+
+		" host12\t3600\tIN\tA\t10.0.0.12\n"+
+		" host13\t3600\tIN\tA\t10.0.0.13\n"+
+		" host14\t3600\tIN\tA\t10.0.0.14\n"+
+		"-host15\t3600\tIN\tA\t10.0.0.15\n"+
+		"+host15\t3600\tIN\tA\t10.0.1.1\n"+
+		" host16\t3600\tIN\tA\t10.0.0.16\n"+
+		" host17\t3600\tIN\tA\t10.0.0.17\n"+
+		" host18\t3600\tIN\tA\t10.0.0.18\n", unified.String())
+}
@@ -0,0 +1,104 @@
+package selectel
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// ImportOptions configures ImportZone.
+type ImportOptions struct {
+	// Filename is used for resolving relative $INCLUDE paths and in error messages.
+	Filename string
+	// AllowInclude enables $INCLUDE directives.
+	AllowInclude bool
+	// IncludeSOA enables importing SOA records.
+	IncludeSOA bool
+	// IncludeApexNS enables importing NS records at the zone apex.
+	IncludeApexNS bool
+	// TTLPolicy defines how TTL of an RR set is chosen when records disagree.
+	TTLPolicy TTLPolicy
+}
+
+// ImportZone parses an RFC 1035 master file for the zone and converts it to RR sets.
+// $ORIGIN defaults to the zone name. SOA and apex NS records are skipped by default,
+// see Skip for excluding them from the current zone state when computing Diff.
+func ImportZone(r io.Reader, zone string, opts ImportOptions) (map[RRSetKey]*RRSet, error) {
+	origin := dns.Fqdn(zone)
+	parser := dns.NewZoneParser(r, origin, opts.Filename)
+	parser.SetIncludeAllowed(opts.AllowInclude)
+
+	var records []libdns.Record
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		header := rr.Header()
+		if !dns.IsSubDomain(origin, header.Name) {
+			return nil, errors.Errorf("%s is outside of zone %s", header.Name, origin)
+		}
+
+		record := libdns.RR{
+			Name: header.Name,
+			Type: dns.TypeToString[header.Rrtype],
+			TTL:  time.Duration(header.Ttl) * time.Second,
+			Data: strings.TrimPrefix(rr.String(), header.String()),
+		}
+
+		if txt, ok := rr.(*dns.TXT); ok {
+			var data strings.Builder
+			for _, s := range txt.Txt {
+				data.WriteString(unescapeTXT(s))
+			}
+
+			record.Data = data.String()
+		}
+
+		if opts.skip(normalizeName(record.Name, zone), record.Type) {
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	if err := parser.Err(); err != nil {
+		return nil, errors.Wrap(err, "parse zone file")
+	}
+
+	sets, err := fromRecords(records, zone, opts.TTLPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert records")
+	}
+
+	resolveTTLs(nil, sets)
+
+	if err := validateRRSets(sets, nil); err != nil {
+		return nil, errors.Wrap(err, "validate records")
+	}
+
+	return sets, nil
+}
+
+// Skip removes RR sets which would be skipped by ImportZone with opts from sets.
+func (opts ImportOptions) Skip(sets map[RRSetKey]*RRSet) map[RRSetKey]*RRSet {
+	result := make(map[RRSetKey]*RRSet, len(sets))
+	for key, set := range sets {
+		if !opts.skip(key.Name, key.Type) {
+			result[key] = set
+		}
+	}
+
+	return result
+}
+
+func (opts ImportOptions) skip(name, typ string) bool {
+	switch {
+	case typ == "SOA":
+		return !opts.IncludeSOA
+	case typ == "NS" && isApex(name):
+		return !opts.IncludeApexNS
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package selectel
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportZone(t *testing.T) {
+	file, err := os.Open("testdata/zone1.org.zone")
+	require.NoError(t, err)
+	defer file.Close()
+
+	sets, err := ImportZone(file, "zone1.org.", ImportOptions{Filename: file.Name(), AllowInclude: true})
+	require.NoError(t, err)
+	assert.Equal(t, map[RRSetKey]*RRSet{
+		{Name: "@", Type: "MX"}: {
+			Key: RRSetKey{Name: "@", Type: "MX"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("10 mx.zone1.org.")},
+		},
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			TTL: 5 * time.Minute,
+			RRs: RRs{SetOf("1.1.1.1", "2.2.2.2")},
+		},
+		{Name: "txt", Type: "TXT"}: {
+			Key: RRSetKey{Name: "txt", Type: "TXT"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("v=spf1 include:_spf.zone1.org ~all")},
+		},
+		{Name: "sub", Type: "NS"}: {
+			Key: RRSetKey{Name: "sub", Type: "NS"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("ns1.other.org.")},
+		},
+		{Name: "api.sub", Type: "CNAME"}: {
+			Key: RRSetKey{Name: "api.sub", Type: "CNAME"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("www.zone1.org.")},
+		},
+	}, sets)
+
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+	_, err = ImportZone(file, "zone1.org.", ImportOptions{Filename: file.Name()})
+	assert.ErrorContains(t, err, "$INCLUDE directive not allowed")
+
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+	sets, err = ImportZone(file, "zone1.org.", ImportOptions{Filename: file.Name(), AllowInclude: true, IncludeSOA: true, IncludeApexNS: true})
+	require.NoError(t, err)
+	assert.Contains(t, sets, RRSetKey{Name: "@", Type: "SOA"})
+	assert.Equal(t, SetOf("ns1.selectel.org.", "ns2.selectel.org."), sets[RRSetKey{Name: "@", Type: "NS"}].RRs[enabled])
+
+	_, err = ImportZone(strings.NewReader("www.zone2.org. 60 IN A 1.1.1.1\n"), "zone1.org.", ImportOptions{})
+	assert.ErrorContains(t, err, "www.zone2.org. is outside of zone zone1.org.")
+
+	_, err = ImportZone(strings.NewReader("www 60 IN A 1.1.1.1\nwww 60 IN CNAME zone2.org.\n"), "zone1.org.", ImportOptions{})
+	assert.ErrorContains(t, err, "CNAME coexists with other records")
+}
+
+func TestImportZone_RoundTrip(t *testing.T) {
+	key := RRSetKey{Name: "txt", Type: "TXT"}
+	sets := map[RRSetKey]*RRSet{
+		key: {
+			Key: key,
+			TTL: time.Hour,
+			RRs: RRs{SetOf(`4"5"6`, `a\b`, "tab\there\x01", strings.Repeat(`x\"`, 100)), SetOf[string]()},
+		},
+	}
+
+	var b strings.Builder
+	require.NoError(t, ExportZone(&b, "zone1.org.", sets, ExportOptions{}))
+
+	imported, err := ImportZone(strings.NewReader(b.String()), "zone1.org.", ImportOptions{})
+	require.NoError(t, err)
+	require.Contains(t, imported, key)
+	assert.Equal(t, sets[key].RRs[enabled], imported[key].RRs[enabled])
+	assert.Empty(t, Diff(sets, imported))
+}
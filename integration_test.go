@@ -1,18 +1,17 @@
 //go:build integration
 
-package selectel
+package selectel_test
 
 import (
-	"context"
 	"testing"
 
 	"github.com/caarlos0/env/v11"
-	"github.com/davecgh/go-spew/spew"
-	"github.com/libdns/libdns"
 	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
 )
 
-func TestProvider(t *testing.T) {
+func TestProvider_Integration(t *testing.T) {
 	var creds struct {
 		Username    string `env:"USERNAME,required"`
 		Password    string `env:"PASSWORD,required"`
@@ -23,29 +22,10 @@ func TestProvider(t *testing.T) {
 	err := env.Parse(&creds)
 	require.NoError(t, err)
 
-	provider := NewProvider(NewClient(Credentials{
+	testScenario(t, selectel.NewProvider(selectel.NewClient(selectel.Credentials{
 		Username:    creds.Username,
 		Password:    creds.Password,
 		AccountID:   creds.AccountID,
 		ProjectName: creds.ProjectName,
-	}))
-
-	ctx := context.Background()
-
-	zones, err := provider.ListZones(ctx)
-	require.NoError(t, err)
-	spew.Dump(zones)
-
-	if len(zones) > 0 {
-		records, err := provider.GetRecords(ctx, zones[0].Name)
-		require.NoError(t, err)
-		spew.Dump(records)
-
-		records, err = provider.AppendRecords(ctx, zones[0].Name, []libdns.Record{
-			libdns.TXT{Name: "libdns-integration-test", Text: `4"5"6`},
-		})
-
-		require.NoError(t, err)
-		spew.Dump(records)
-	}
+	})))
 }
@@ -0,0 +1,219 @@
+package selectel
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// JournalEntry describes RR set changes made by a single Provider method call.
+type JournalEntry struct {
+	Time    time.Time       `json:"time"`
+	Method  string          `json:"method"`
+	Zone    string          `json:"zone"`
+	Changes []JournalChange `json:"changes"`
+}
+
+// JournalChange describes a change of a single RR set.
+type JournalChange struct {
+	Op   ChangeOp `json:"op"`
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+
+	// Before is nil for created RR sets.
+	Before *JournalState `json:"before,omitempty"`
+	// After is nil for deleted RR sets.
+	After *JournalState `json:"after,omitempty"`
+}
+
+// JournalState contains TTL and data of enabled and disabled records of an RR set.
+type JournalState struct {
+	// TTL in seconds.
+	TTL      int      `json:"ttl"`
+	Data     []string `json:"data"`
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+func newJournalState(ttl time.Duration, records, disabled []libdns.Record) *JournalState {
+	state := &JournalState{TTL: int(getTTL(ttl).Seconds())}
+	for _, record := range records {
+		state.Data = append(state.Data, record.RR().Data)
+	}
+
+	for _, record := range disabled {
+		state.Disabled = append(state.Disabled, record.RR().Data)
+	}
+
+	return state
+}
+
+func (s *JournalState) ttl() time.Duration {
+	return time.Duration(s.TTL) * time.Second
+}
+
+func (s *JournalState) rrs() RRs {
+	return RRs{SetOf(s.Data...), SetOf(s.Disabled...)}
+}
+
+func (s *JournalState) match(set *RRSet) bool {
+	rrs := s.rrs()
+	return s.ttl() == getTTL(set.TTL) && maps.Equal(set.RRs[enabled], rrs[enabled]) && maps.Equal(set.RRs[disabled], rrs[disabled])
+}
+
+// Journal writes journal entries as JSON lines.
+// Assign it to Provider.Journal to record all changes made by the provider.
+type Journal struct {
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// NewJournal creates a Journal writing to w.
+// Use a file opened with os.O_APPEND to keep a durable journal.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{encoder: json.NewEncoder(w)}
+}
+
+// Write appends the entry to the journal.
+func (j *Journal) Write(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.encoder.Encode(entry)
+}
+
+// ReadJournal reads all entries written by Journal.
+func ReadJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal entry %d", len(entries)+1)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// writeJournal records successful changes made by method if Journal is set.
+func (p *Provider) writeJournal(method, zone string, changes []ChangeEvent) error {
+	if p.Journal == nil {
+		return nil
+	}
+
+	entry := JournalEntry{
+		Time:   time.Now().UTC(),
+		Method: method,
+		Zone:   zone,
+	}
+
+	for _, change := range changes {
+		if change.Err != nil {
+			continue
+		}
+
+		jc := JournalChange{
+			Op:   change.Op,
+			Name: change.Key.Name,
+			Type: change.Key.Type,
+		}
+
+		if change.Op != ChangeCreate {
+			jc.Before = newJournalState(change.BeforeTTL, change.Before, change.BeforeDisabled)
+		}
+
+		if change.Op != ChangeDelete {
+			jc.After = newJournalState(change.TTL, change.After, change.AfterDisabled)
+		}
+
+		entry.Changes = append(entry.Changes, jc)
+	}
+
+	if len(entry.Changes) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(p.Journal.Write(entry), "write journal")
+}
+
+// Undo restores the state of RR sets prior to the changes described by entry.
+// Changes are reverted in reverse order. If any RR set has been changed since
+// (its state does not match the entry), nothing is reverted and an error is returned.
+// Reverting changes is recorded in Journal as well.
+func (p *Provider) Undo(ctx context.Context, entry JournalEntry) (errs error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "Undo", zoneKey.String(entry.Zone))
+	defer func() { endSpan(span, errs) }()
+
+	sets, err := p.client().GetRRSets(ctx, entry.Zone)
+	if err != nil {
+		return errors.Wrap(err, "get RR sets")
+	}
+
+	for _, change := range entry.Changes {
+		key := RRSetKey{Name: change.Name, Type: change.Type}
+		set, ok := sets[key]
+		changed := ok
+		if change.After != nil {
+			changed = !ok || !change.After.match(set)
+		}
+
+		if changed {
+			_ = multierr.AppendInto(&errs, errors.Errorf("%s has changed since %s", key, entry.Time.Format(time.RFC3339)))
+		}
+	}
+
+	if errs != nil {
+		return
+	}
+
+	var changes []ChangeEvent
+	defer func() { _ = multierr.AppendInto(&errs, p.writeJournal("Undo", entry.Zone, changes)) }()
+
+	for _, change := range slices.Backward(entry.Changes) {
+		key := RRSetKey{Name: change.Name, Type: change.Type}
+		set, ok := sets[key]
+		switch {
+		case change.Before == nil:
+			err := p.client().DeleteRRSet(ctx, entry.Zone, set.ID)
+			changes = append(changes, p.notify(ctx, ChangeDelete, entry.Zone, set, set, err))
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "delete %s", key))
+
+		case !ok:
+			set := &RRSet{
+				Key: key,
+				TTL: change.Before.ttl(),
+				RRs: change.Before.rrs(),
+			}
+
+			err := p.client().CreateRRSet(ctx, entry.Zone, set)
+			changes = append(changes, p.notify(ctx, ChangeCreate, entry.Zone, set, nil, err))
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", key))
+
+		default:
+			before := set.clone()
+			set.TTL = change.Before.ttl()
+			set.RRs = change.Before.rrs()
+
+			err := p.client().UpdateRRSet(ctx, entry.Zone, set)
+			changes = append(changes, p.notify(ctx, ChangeUpdate, entry.Zone, set, before, err))
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", key))
+		}
+	}
+
+	return
+}
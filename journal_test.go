@@ -0,0 +1,153 @@
+package selectel_test
+
+import (
+	"bytes"
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Undo(t *testing.T) {
+	ctx := context.Background()
+	client := selecteltest.NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.",
+		&selectel.RRSet{
+			Key: selectel.RRSetKey{Name: "rrset1", Type: "A"},
+			TTL: time.Hour,
+			RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), selectel.SetOf("3.3.3.3")},
+		},
+		&selectel.RRSet{
+			Key: selectel.RRSetKey{Name: "rrset2", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: selectel.RRs{selectel.SetOf("hello"), nil},
+		},
+	))
+
+	var buf bytes.Buffer
+	provider := selectel.NewProvider(client)
+	initial, err := provider.GetRecords(ctx, "zone1.org.")
+	require.NoError(t, err)
+
+	provider.Journal = selectel.NewJournal(&buf)
+
+	_, err = provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.MustParseAddr("2.2.2.2")},
+		libdns.TXT{Name: "rrset3", TTL: time.Minute, Text: "world"},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: 2 * time.Hour, IP: netip.MustParseAddr("4.4.4.4")},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.RR{Name: "rrset2"},
+	})
+	require.NoError(t, err)
+
+	// nothing is changed, so nothing is recorded
+	_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.RR{Name: "rrset4"},
+	})
+	require.NoError(t, err)
+
+	entries, err := selectel.ReadJournal(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "AppendRecords", entries[0].Method)
+	assert.Equal(t, []selectel.JournalChange{
+		{
+			Op:    selectel.ChangeCreate,
+			Name:  "rrset3",
+			Type:  "TXT",
+			After: &selectel.JournalState{TTL: 60, Data: []string{"world"}},
+		},
+		{
+			Op:     selectel.ChangeUpdate,
+			Name:   "rrset1",
+			Type:   "A",
+			Before: &selectel.JournalState{TTL: 3600, Data: []string{"1.1.1.1"}, Disabled: []string{"3.3.3.3"}},
+			After:  &selectel.JournalState{TTL: 3600, Data: []string{"1.1.1.1", "2.2.2.2"}, Disabled: []string{"3.3.3.3"}},
+		},
+	}, entries[0].Changes)
+	assert.Equal(t, "SetRecords", entries[1].Method)
+	assert.Equal(t, "DeleteRecords", entries[2].Method)
+
+	// undoing an older entry first conflicts with the newer change
+	assert.ErrorContains(t, provider.Undo(ctx, entries[0]), "A rrset1 has changed since")
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		require.NoError(t, provider.Undo(ctx, entries[i]))
+	}
+
+	records, err := provider.GetRecords(ctx, "zone1.org.")
+	require.NoError(t, err)
+	assert.Equal(t, initial, records)
+	assert.Equal(t, selectel.SetOf("3.3.3.3"), client.RRSets("zone1.org.")[selectel.RRSetKey{Name: "rrset1", Type: "A"}].RRs[1])
+
+	// undo operations are recorded as well
+	entries, err = selectel.ReadJournal(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+	assert.Equal(t, "Undo", entries[5].Method)
+}
+
+func TestProvider_Undo_Disabled(t *testing.T) {
+	ctx := context.Background()
+	key := selectel.RRSetKey{Name: "www", Type: "A"}
+	client := selecteltest.NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.", &selectel.RRSet{
+		Key: key,
+		TTL: time.Hour,
+		RRs: selectel.RRs{selectel.SetOf[string](), selectel.SetOf("1.1.1.1")},
+	}))
+
+	var buf bytes.Buffer
+	provider := selectel.NewProvider(client)
+	provider.Journal = selectel.NewJournal(&buf)
+	provider.DeleteDisabled = true
+
+	// re-enable the disabled record with a different TTL
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "www", TTL: 5 * time.Minute, IP: netip.MustParseAddr("1.1.1.1")},
+	})
+	require.NoError(t, err)
+
+	entries, err := selectel.ReadJournal(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, &selectel.JournalState{TTL: 3600, Disabled: []string{"1.1.1.1"}}, entries[0].Changes[0].Before)
+
+	require.NoError(t, provider.Undo(ctx, entries[0]))
+	set := client.RRSets("zone1.org.")[key]
+	require.NotNil(t, set)
+	assert.Equal(t, time.Hour, set.TTL)
+	assert.Empty(t, set.RRs[0])
+	assert.Equal(t, selectel.SetOf("1.1.1.1"), set.RRs[1])
+
+	// delete the disabled record and restore it
+	buf.Reset()
+	_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{libdns.RR{Name: "www", Type: "A"}})
+	require.NoError(t, err)
+	assert.NotContains(t, client.RRSets("zone1.org."), key)
+
+	entries, err = selectel.ReadJournal(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.NoError(t, provider.Undo(ctx, entries[0]))
+
+	set = client.RRSets("zone1.org.")[key]
+	require.NotNil(t, set)
+	assert.Equal(t, time.Hour, set.TTL)
+	assert.Empty(t, set.RRs[0])
+	assert.Equal(t, selectel.SetOf("1.1.1.1"), set.RRs[1])
+}
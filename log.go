@@ -0,0 +1,15 @@
+package selectel
+
+import (
+	"log/slog"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+func errorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.String("error", err.Error())
+}
@@ -0,0 +1,109 @@
+package selectel
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics collects Selectel API usage statistics.
+// It implements prometheus.Collector and should be registered by the application
+// and passed to NewClient with WithMetrics.
+// All methods are safe to call on nil Metrics.
+type Metrics struct {
+	requests      *prometheus.CounterVec
+	retries       *prometheus.CounterVec
+	authorization *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	tokenExpiry   prometheus.Gauge
+}
+
+// NewMetrics creates Metrics with names prefixed by the specified namespace.
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "selectel",
+			Name:      "requests_total",
+			Help:      "Number of Selectel API requests by endpoint and response status.",
+		}, []string{"endpoint", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "selectel",
+			Name:      "retries_total",
+			Help:      "Number of retried Selectel API requests by endpoint.",
+		}, []string{"endpoint"}),
+		authorization: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "selectel",
+			Name:      "authorizations_total",
+			Help:      "Number of token requests by reason (initial, expired or rejected).",
+		}, []string{"reason"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "selectel",
+			Name:      "request_duration_seconds",
+			Help:      "Selectel API request latency by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		tokenExpiry: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "selectel",
+			Name:      "token_expiry_timestamp_seconds",
+			Help:      "Expiration time of the current API token as Unix timestamp.",
+		}),
+	}
+}
+
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.retries.Describe(ch)
+	m.authorization.Describe(ch)
+	m.latency.Describe(ch)
+	m.tokenExpiry.Describe(ch)
+}
+
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.retries.Collect(ch)
+	m.authorization.Collect(ch)
+	m.latency.Collect(ch)
+	m.tokenExpiry.Collect(ch)
+}
+
+// observeRequest records a request attempt. Zero status denotes a transport error.
+func (m *Metrics) observeRequest(endpoint string, status int, attempt int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+
+	m.requests.WithLabelValues(endpoint, label).Inc()
+	m.latency.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if attempt > 1 {
+		m.retries.WithLabelValues(endpoint).Inc()
+	}
+}
+
+func (m *Metrics) observeAuthorization(reason string) {
+	if m == nil {
+		return
+	}
+
+	m.authorization.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) setTokenExpiry(expiresAt time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.tokenExpiry.Set(float64(expiresAt.Unix()))
+}
+
+var _ prometheus.Collector = (*Metrics)(nil)
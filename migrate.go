@@ -0,0 +1,151 @@
+package selectel
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+	v2 "github.com/selectel/domains-go/pkg/v2"
+)
+
+// SupportedTypes lists record types supported by Selectel DNS.
+var SupportedTypes = []string{
+	string(v2.A),
+	string(v2.AAAA),
+	string(v2.ALIAS),
+	string(v2.CAA),
+	string(v2.CNAME),
+	string(v2.MX),
+	string(v2.NS),
+	string(v2.SOA),
+	string(v2.SRV),
+	string(v2.SSHFP),
+	string(v2.TXT),
+}
+
+// MigrationTarget is a libdns provider records are copied to by Migrate.
+type MigrationTarget interface {
+	libdns.RecordGetter
+	libdns.RecordSetter
+}
+
+// MigrateOptions configures Migrate.
+type MigrateOptions struct {
+	// DryRun disables writing records, the report describes what would be copied.
+	DryRun bool
+	// Types lists record types the destination supports. Records of other types are reported as unsupported.
+	// Defaults to SupportedTypes if the destination is a Provider, otherwise all types are copied.
+	Types []string
+	// IncludeSOA enables copying the SOA record.
+	IncludeSOA bool
+	// IncludeApexNS enables copying NS records at the zone apex.
+	IncludeApexNS bool
+}
+
+// MigrateReport describes records handled by Migrate. Records are sorted by name, type and data.
+type MigrateReport struct {
+	// Copied lists records written to the destination (or to be written in dry-run mode).
+	Copied []libdns.Record
+	// Unsupported lists records of types not supported by the destination.
+	Unsupported []libdns.Record
+	// Skipped lists SOA and apex NS records which are managed by DNS hosting.
+	Skipped []libdns.Record
+	// Missing lists copied records which were not found in the destination during verification.
+	Missing []libdns.Record
+}
+
+// UnsupportedTypes returns sorted types of unsupported records.
+func (r *MigrateReport) UnsupportedTypes() []string {
+	var types []string
+	for _, record := range r.Unsupported {
+		types = append(types, record.RR().Type)
+	}
+
+	slices.Sort(types)
+	return slices.Compact(types)
+}
+
+// Migrate copies records of the zone from src to dst with a single SetRecords call
+// and verifies that all copied records are present in dst afterwards.
+// Any libdns provider may be used as src or dst, e.g. to migrate zones to Provider or from it.
+// Records are translated into Selectel RR sets if dst is a Provider, so the copy fails early on invalid records.
+// Disabled records of a Provider src are not copied.
+func Migrate(ctx context.Context, src libdns.RecordGetter, dst MigrationTarget, zone string, opts MigrateOptions) (*MigrateReport, error) {
+	records, err := src.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, errors.Wrap(err, "get source records")
+	}
+
+	provider, _ := dst.(*Provider)
+	types := opts.Types
+	if types == nil && provider != nil {
+		types = SupportedTypes
+	}
+
+	report := new(MigrateReport)
+	for _, record := range records {
+		rr := record.RR()
+		name := normalizeName(rr.Name, zone)
+		switch {
+		case rr.Type == "SOA" && !opts.IncludeSOA,
+			rr.Type == "NS" && isApex(name) && !opts.IncludeApexNS:
+			report.Skipped = append(report.Skipped, record)
+		case types != nil && !slices.Contains(types, rr.Type):
+			report.Unsupported = append(report.Unsupported, record)
+		default:
+			report.Copied = append(report.Copied, record)
+		}
+	}
+
+	for _, records := range [][]libdns.Record{report.Copied, report.Unsupported, report.Skipped} {
+		slices.SortFunc(records, compareRecords)
+	}
+
+	if provider != nil {
+		sets, err := fromRecords(report.Copied, zone, provider.TTLPolicy)
+		if err != nil {
+			return report, errors.Wrap(err, "convert records")
+		}
+
+		if err := validateRRSets(sets, nil); err != nil {
+			return report, errors.Wrap(err, "validate records")
+		}
+	}
+
+	if opts.DryRun || len(report.Copied) == 0 {
+		return report, nil
+	}
+
+	_, err = dst.SetRecords(ctx, zone, report.Copied)
+	err = errors.Wrap(err, "set records")
+
+	written, verr := dst.GetRecords(ctx, zone)
+	if verr != nil {
+		return report, errors.Wrap(verr, "get destination records")
+	}
+
+	present := make(Set[string])
+	for _, record := range written {
+		present[migrationKey(record, zone)] = true
+	}
+
+	for _, record := range report.Copied {
+		if !present[migrationKey(record, zone)] {
+			report.Missing = append(report.Missing, record)
+		}
+	}
+
+	if err == nil && len(report.Missing) > 0 {
+		err = errors.Errorf("%d records are missing after copy", len(report.Missing))
+	}
+
+	return report, err
+}
+
+// migrationKey identifies a record regardless of its TTL, which may be adjusted by the destination.
+func migrationKey(record libdns.Record, zone string) string {
+	rr := record.RR()
+	return strings.Join([]string{strings.ToLower(normalizeName(rr.Name, zone)), rr.Type, rr.Data}, " ")
+}
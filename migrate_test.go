@@ -0,0 +1,89 @@
+package selectel_test
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+// memoryProvider is a minimal libdns provider keeping records in memory.
+type memoryProvider struct {
+	records []libdns.Record
+	drop    string
+}
+
+func (p *memoryProvider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	return slices.Clone(p.records), nil
+}
+
+func (p *memoryProvider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	for _, record := range records {
+		rr := record.RR()
+		p.records = slices.DeleteFunc(p.records, func(r libdns.Record) bool {
+			return r.RR().Name == rr.Name && r.RR().Type == rr.Type
+		})
+	}
+
+	for _, record := range records {
+		if record.RR().Type != p.drop {
+			p.records = append(p.records, record)
+		}
+	}
+
+	return records, nil
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	www := libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("1.1.1.1")}
+	txt := libdns.TXT{Name: "@", TTL: time.Hour, Text: "v=spf1 -all"}
+	mx := libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mx.zone1.org."}
+	soa := libdns.RR{Name: "@", TTL: time.Hour, Type: "SOA", Data: "ns1.other.org. admin.zone1.org. 1 3600 600 604800 60"}
+	ns := libdns.NS{Name: "@", TTL: time.Hour, Target: "ns1.other.org."}
+	svcb := libdns.RR{Name: "_svc", TTL: time.Hour, Type: "SVCB", Data: `1 . alpn="h2"`}
+
+	src := &memoryProvider{records: []libdns.Record{www, txt, mx, soa, ns, svcb}}
+	client := selecteltest.NewClient("zone1.org.")
+	provider := selectel.NewProvider(client)
+
+	report, err := selectel.Migrate(ctx, src, provider, "zone1.org.", selectel.MigrateOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, &selectel.MigrateReport{
+		Copied:      []libdns.Record{mx, txt, www},
+		Unsupported: []libdns.Record{svcb},
+		Skipped:     []libdns.Record{ns, soa},
+	}, report)
+	assert.Equal(t, []string{"SVCB"}, report.UnsupportedTypes())
+	assert.Empty(t, client.RRSets("zone1.org."))
+
+	report, err = selectel.Migrate(ctx, src, provider, "zone1.org.", selectel.MigrateOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Len(t, client.RRSets("zone1.org."), 3)
+
+	t.Run("from provider", func(t *testing.T) {
+		dst := &memoryProvider{}
+		report, err := selectel.Migrate(ctx, provider, dst, "zone1.org.", selectel.MigrateOptions{Types: []string{"A", "MX"}})
+		require.NoError(t, err)
+		assert.Len(t, report.Copied, 2)
+		assert.Equal(t, []string{"TXT"}, report.UnsupportedTypes())
+		assert.Len(t, dst.records, 2)
+	})
+
+	t.Run("verification", func(t *testing.T) {
+		dst := &memoryProvider{drop: "MX"}
+		report, err := selectel.Migrate(ctx, provider, dst, "zone1.org.", selectel.MigrateOptions{})
+		assert.EqualError(t, err, "1 records are missing after copy")
+		require.Len(t, report.Missing, 1)
+		assert.Equal(t, "MX", report.Missing[0].RR().Type)
+	})
+}
@@ -1,16 +1,18 @@
 package selectel
 
 import (
+	"cmp"
 	"fmt"
 	"iter"
 	"maps"
 	"slices"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
 	v2 "github.com/selectel/domains-go/pkg/v2"
+	"go.uber.org/multierr"
 )
 
 type RRSetKey struct {
@@ -22,6 +24,25 @@ func (k RRSetKey) String() string {
 	return fmt.Sprintf("%s %s", k.Type, k.Name)
 }
 
+// Compare orders keys by name, then by type.
+func (k RRSetKey) Compare(other RRSetKey) int {
+	return cmp.Or(strings.Compare(k.Name, other.Name), strings.Compare(k.Type, other.Type))
+}
+
+func sortedKeys(sets map[RRSetKey]*RRSet) []RRSetKey {
+	return slices.SortedFunc(maps.Keys(sets), RRSetKey.Compare)
+}
+
+// compareRecords orders records by name, type, then data.
+func compareRecords(a, b libdns.Record) int {
+	ra, rb := a.RR(), b.RR()
+	return cmp.Or(
+		strings.Compare(ra.Name, rb.Name),
+		strings.Compare(ra.Type, rb.Type),
+		strings.Compare(ra.Data, rb.Data),
+	)
+}
+
 const (
 	enabled  int = 0
 	disabled int = 1
@@ -40,10 +61,19 @@ func (s *RRSet) matchEnabledRRs(other *RRSet) bool {
 	return maps.Equal(s.RRs[enabled], other.RRs[enabled])
 }
 
+// matchRecord reports whether the record of the set with the specified data matches the pattern.
+// Pattern name is expected to be normalized. Empty type, zero TTL and empty data match any value.
+func (s *RRSet) matchRecord(data string, pattern libdns.RR) bool {
+	return s.Key.Name == pattern.Name &&
+		(pattern.Type == "" || pattern.Type == s.Key.Type) &&
+		(pattern.TTL == 0 || pattern.TTL == s.TTL) &&
+		(pattern.Data == "" || pattern.Data == data)
+}
+
 func fromSelectel(rrs *v2.RRSet, zone string) *RRSet {
 	set := &RRSet{
 		Key: RRSetKey{
-			Name: libdns.RelativeName(rrs.Name, zone),
+			Name: normalizeName(rrs.Name, zone),
 			Type: string(rrs.Type),
 		},
 		ID:  rrs.ID,
@@ -72,6 +102,15 @@ func fromSelectel(rrs *v2.RRSet, zone string) *RRSet {
 	return set
 }
 
+func (s *RRSet) clone() *RRSet {
+	result := *s
+	for idx := range result.RRs {
+		result.RRs[idx] = maps.Clone(result.RRs[idx])
+	}
+
+	return &result
+}
+
 func (s *RRSet) toSelectel(zone string) *v2.RRSet {
 	set := &v2.RRSet{
 		ID:   s.ID,
@@ -81,7 +120,7 @@ func (s *RRSet) toSelectel(zone string) *v2.RRSet {
 		Records: slices.Collect(func(yield func(v2.RecordItem) bool) {
 			for idx := range s.RRs {
 				disabled := idx == disabled
-				for data := range s.RRs[idx] {
+				for _, data := range sorted(s.RRs[idx]) {
 					if s.Key.Type == "TXT" {
 						data = `"` + strings.NewReplacer(`"`, `\"`).Replace(data) + `"`
 					}
@@ -99,20 +138,32 @@ func (s *RRSet) toSelectel(zone string) *v2.RRSet {
 		}),
 	}
 
-	// for tests
-	sort.Slice(set.Records, func(i, j int) bool { return set.Records[i].Content < set.Records[j].Content })
+	// records are sent ordered by content regardless of their state
+	slices.SortStableFunc(set.Records, func(a, b v2.RecordItem) int { return strings.Compare(a.Content, b.Content) })
 
 	return set
 }
 
-func fromRecords(records []libdns.Record) map[RRSetKey]*RRSet {
+// fromRecords groups records into RR sets. RR sets with no TTL specified get zero TTL (see resolveTTLs).
+func fromRecords(records []libdns.Record, zone string, policy TTLPolicy) (map[RRSetKey]*RRSet, error) {
+	var errs error
 	result := make(map[RRSetKey]*RRSet)
+	ttls := make(map[RRSetKey][]time.Duration)
 	for _, record := range records {
 		key := RRSetKey{
-			Name: record.RR().Name,
+			Name: normalizeName(record.RR().Name, zone),
 			Type: record.RR().Type,
 		}
 
+		if err := validateName(key.Name); err != nil {
+			_ = multierr.AppendInto(&errs, &ValidationError{
+				Reason:  err.Error(),
+				Records: []libdns.Record{record},
+			})
+
+			continue
+		}
+
 		set := result[key]
 		if set == nil {
 			set = &RRSet{
@@ -125,16 +176,29 @@ func fromRecords(records []libdns.Record) map[RRSetKey]*RRSet {
 			result[key] = set
 		}
 
-		set.TTL = getTTL(set.TTL, record.RR().TTL)
+		ttls[key] = append(ttls[key], record.RR().TTL)
 		set.RRs[enabled][record.RR().Data] = true
 	}
 
-	return result
+	for key, set := range result {
+		ttl, err := policy.apply(ttls[key]...)
+		if multierr.AppendInto(&errs, errors.Wrapf(err, "%s", key)) {
+			continue
+		}
+
+		set.TTL = ttl
+	}
+
+	return result, errs
 }
 
 func (s *RRSet) toRecords() iter.Seq[libdns.Record] {
+	return s.records(enabled)
+}
+
+func (s *RRSet) records(state int) iter.Seq[libdns.Record] {
 	return func(yield func(libdns.Record) bool) {
-		for data := range s.RRs[enabled] {
+		for _, data := range sorted(s.RRs[state]) {
 			rr := libdns.RR{
 				Name: s.Key.Name,
 				Type: s.Key.Type,
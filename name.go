@@ -0,0 +1,43 @@
+package selectel
+
+import (
+	"strings"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+)
+
+// apex is the normalized name of the zone apex.
+const apex = "@"
+
+// normalizeName converts the name to the form used in RRSetKey.
+// Absolute names are made relative to the zone, all spellings of the zone apex
+// ("", "@" and the zone name itself) are converted to "@".
+func normalizeName(name, zone string) string {
+	if strings.HasSuffix(name, ".") {
+		name = libdns.RelativeName(name, zone)
+	}
+
+	if name == "" || name == apex {
+		return apex
+	}
+
+	return name
+}
+
+// validateName checks that the wildcard label is used only as the leftmost label of the name.
+func validateName(name string) error {
+	if !strings.Contains(name, "*") {
+		return nil
+	}
+
+	if name != "*" && (!strings.HasPrefix(name, "*.") || strings.Contains(name[2:], "*")) {
+		return errors.New("wildcard is allowed only as the leftmost label")
+	}
+
+	return nil
+}
+
+func isApex(name string) bool {
+	return name == apex
+}
@@ -0,0 +1,69 @@
+package selectel
+
+import (
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures Client created with NewClient.
+type Option func(*options)
+
+type options struct {
+	authURL    string
+	apiURL     string
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	tracerProvider trace.TracerProvider
+	metrics        *Metrics
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		authURL:    authTokenURL,
+		apiURL:     domainsApiURL,
+		httpClient: new(http.Client),
+		logger:     discardLogger,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithAuthURL overrides the URL used for obtaining project-scoped tokens.
+func WithAuthURL(url string) Option {
+	return func(o *options) { o.authURL = url }
+}
+
+// WithAPIURL overrides the base URL of Selectel Domains API v2.
+func WithAPIURL(url string) Option {
+	return func(o *options) { o.apiURL = url }
+}
+
+// WithHTTPClient sets the HTTP client used for all requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) { o.httpClient = client }
+}
+
+// WithLogger sets the logger for API calls, retries and authorization.
+// All records are logged at debug level. Credentials and tokens are never logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used for creating spans
+// for API calls and authorization. No spans are recorded by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) { o.tracerProvider = provider }
+}
+
+// WithMetrics sets the collector for API request metrics.
+// The collector should be registered by the caller.
+func WithMetrics(metrics *Metrics) Option {
+	return func(o *options) { o.metrics = metrics }
+}
@@ -0,0 +1,129 @@
+package selectel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// ErrNotOwned is returned for RR sets which are not owned by Provider.OwnerID.
+var ErrNotOwned = errors.New("RR set is not owned")
+
+const (
+	ownerMarkerPrefix = "_libdns-"
+	ownerHeritage     = "heritage=libdns-selectel"
+)
+
+// ownerMarkerKey returns the key of the TXT RR set marking ownership of the RR set with the specified key.
+// For example, ownership of A www is marked with TXT _libdns-a.www.
+func ownerMarkerKey(key RRSetKey) RRSetKey {
+	label := ownerMarkerPrefix + strings.ToLower(key.Type)
+	name := key.Name
+	switch {
+	case isApex(name):
+		name = label
+	case name == "*":
+		name = label + "._wildcard"
+	case strings.HasPrefix(name, "*."):
+		name = label + "._wildcard." + name[2:]
+	default:
+		name = label + "." + name
+	}
+
+	return RRSetKey{Name: name, Type: "TXT"}
+}
+
+func isOwnerMarker(key RRSetKey) bool {
+	return key.Type == "TXT" && strings.HasPrefix(key.Name, ownerMarkerPrefix)
+}
+
+func (p *Provider) ownerData() string {
+	return ownerHeritage + ",owner=" + p.OwnerID
+}
+
+// hideOwnerMarkers removes ownership markers from sets if ownership mode is enabled.
+func (p *Provider) hideOwnerMarkers(sets map[RRSetKey]*RRSet) {
+	if p.OwnerID == "" {
+		return
+	}
+
+	for key := range sets {
+		if isOwnerMarker(key) {
+			delete(sets, key)
+		}
+	}
+}
+
+// checkOwner returns ErrNotOwned if ownership mode is enabled and the existing RR set
+// with the specified key is not marked as owned by the provider. It succeeds if ForceOwnership is set.
+func (p *Provider) checkOwner(sets map[RRSetKey]*RRSet, key RRSetKey) error {
+	if p.OwnerID == "" || p.ForceOwnership {
+		return nil
+	}
+
+	if p.owns(sets, key) {
+		return nil
+	}
+
+	return errors.Wrapf(ErrNotOwned, "%s", key)
+}
+
+// owns checks if the RR set with the specified key is marked as owned by the provider.
+func (p *Provider) owns(sets map[RRSetKey]*RRSet, key RRSetKey) bool {
+	marker, ok := sets[ownerMarkerKey(key)]
+	return ok && marker.RRs[enabled][p.ownerData()]
+}
+
+// checkOwners removes RR sets which exist in prev and fail checkOwner from next and reports them in errs.
+func (p *Provider) checkOwners(prev, next map[RRSetKey]*RRSet, errs *error) {
+	for _, key := range sortedKeys(next) {
+		if _, ok := prev[key]; !ok {
+			continue
+		}
+
+		if err := p.checkOwner(prev, key); multierr.AppendInto(errs, err) {
+			delete(next, key)
+		}
+	}
+}
+
+// claim creates (or overwrites a stale) ownership marker for the RR set created by the provider.
+func (p *Provider) claim(ctx context.Context, zone string, sets map[RRSetKey]*RRSet, key RRSetKey) ChangeEvent {
+	marker := &RRSet{
+		Key: ownerMarkerKey(key),
+		TTL: MinTTL,
+		RRs: RRs{SetOf(p.ownerData())},
+	}
+
+	if stale, ok := sets[marker.Key]; ok {
+		marker.ID = stale.ID
+		err := p.client().UpdateRRSet(ctx, zone, marker)
+		return p.notify(ctx, ChangeUpdate, zone, marker, stale, err)
+	}
+
+	err := p.client().CreateRRSet(ctx, zone, marker)
+	return p.notify(ctx, ChangeCreate, zone, marker, nil, err)
+}
+
+// claimForced claims the RR set modified with ForceOwnership if it is not owned by the provider yet,
+// so that subsequent calls without ForceOwnership are allowed to modify it.
+func (p *Provider) claimForced(ctx context.Context, zone string, sets map[RRSetKey]*RRSet, key RRSetKey) (ChangeEvent, bool) {
+	if p.OwnerID == "" || p.owns(sets, key) {
+		return ChangeEvent{}, false
+	}
+
+	return p.claim(ctx, zone, sets, key), true
+}
+
+// release deletes the ownership marker of the deleted RR set if it exists.
+func (p *Provider) release(ctx context.Context, zone string, sets map[RRSetKey]*RRSet, key RRSetKey) (ChangeEvent, bool) {
+	marker, ok := sets[ownerMarkerKey(key)]
+	if !ok {
+		return ChangeEvent{}, false
+	}
+
+	err := p.client().DeleteRRSet(ctx, zone, marker.ID)
+	return p.notify(ctx, ChangeDelete, zone, marker, marker, err), true
+}
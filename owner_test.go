@@ -0,0 +1,116 @@
+package selectel_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Ownership(t *testing.T) {
+	ctx := context.Background()
+	client := selecteltest.NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.", &selectel.RRSet{
+		Key: selectel.RRSetKey{Name: "manual", Type: "A"},
+		TTL: time.Hour,
+		RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), nil},
+	}))
+
+	provider := selectel.NewProvider(client)
+	provider.OwnerID = "owner1"
+
+	www := libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("2.2.2.2")}
+	manual := libdns.Address{Name: "manual", TTL: time.Hour, IP: netip.MustParseAddr("3.3.3.3")}
+	manual2 := libdns.Address{Name: "manual", TTL: time.Hour, IP: netip.MustParseAddr("4.4.4.4")}
+	marker := selectel.RRSetKey{Name: "_libdns-a.www", Type: "TXT"}
+	manualMarker := selectel.RRSetKey{Name: "_libdns-a.manual", Type: "TXT"}
+
+	t.Run("create claims ownership", func(t *testing.T) {
+		_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{www})
+		require.NoError(t, err)
+
+		sets := client.RRSets("zone1.org.")
+		require.Contains(t, sets, marker)
+		assert.Equal(t, selectel.SetOf("heritage=libdns-selectel,owner=owner1"), sets[marker].RRs[0])
+	})
+
+	t.Run("markers are hidden", func(t *testing.T) {
+		records, err := provider.GetRecords(ctx, "zone1.org.")
+		require.NoError(t, err)
+		for _, record := range records {
+			assert.NotEqual(t, "TXT", record.RR().Type)
+		}
+	})
+
+	t.Run("unowned RR sets are not modified", func(t *testing.T) {
+		_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{manual})
+		assert.ErrorIs(t, err, selectel.ErrNotOwned)
+
+		_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{libdns.RR{Name: "manual", Type: "A"}})
+		assert.ErrorIs(t, err, selectel.ErrNotOwned)
+
+		set := client.RRSets("zone1.org.")[selectel.RRSetKey{Name: "manual", Type: "A"}]
+		require.NotNil(t, set)
+		assert.Equal(t, selectel.SetOf("1.1.1.1"), set.RRs[0])
+	})
+
+	t.Run("other owners are refused", func(t *testing.T) {
+		other := selectel.NewProvider(client)
+		other.OwnerID = "owner2"
+		_, err := other.DeleteRecords(ctx, "zone1.org.", []libdns.Record{www})
+		assert.ErrorIs(t, err, selectel.ErrNotOwned)
+	})
+
+	t.Run("force ownership", func(t *testing.T) {
+		forced := selectel.NewProvider(client)
+		forced.OwnerID = "owner1"
+		forced.ForceOwnership = true
+		_, err := forced.SetRecords(ctx, "zone1.org.", []libdns.Record{manual})
+		require.NoError(t, err)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Equal(t, selectel.SetOf("3.3.3.3"), sets[selectel.RRSetKey{Name: "manual", Type: "A"}].RRs[0])
+		require.Contains(t, sets, manualMarker)
+		assert.Equal(t, selectel.SetOf("heritage=libdns-selectel,owner=owner1"), sets[manualMarker].RRs[0])
+
+		_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{manual, manual2})
+		require.NoError(t, err)
+	})
+
+	t.Run("force ownership of foreign RR set", func(t *testing.T) {
+		forced := selectel.NewProvider(client)
+		forced.OwnerID = "owner2"
+		forced.ForceOwnership = true
+		_, err := forced.DeleteRecords(ctx, "zone1.org.", []libdns.Record{manual2})
+		require.NoError(t, err)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Equal(t, selectel.SetOf("3.3.3.3"), sets[selectel.RRSetKey{Name: "manual", Type: "A"}].RRs[0])
+		require.Contains(t, sets, manualMarker)
+		assert.Equal(t, selectel.SetOf("heritage=libdns-selectel,owner=owner2"), sets[manualMarker].RRs[0])
+
+		_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{manual2})
+		assert.ErrorIs(t, err, selectel.ErrNotOwned)
+
+		other := selectel.NewProvider(client)
+		other.OwnerID = "owner2"
+		_, err = other.SetRecords(ctx, "zone1.org.", []libdns.Record{manual2})
+		require.NoError(t, err)
+	})
+
+	t.Run("delete releases ownership", func(t *testing.T) {
+		_, err := provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{www})
+		require.NoError(t, err)
+
+		sets := client.RRSets("zone1.org.")
+		assert.NotContains(t, sets, selectel.RRSetKey{Name: "www", Type: "A"})
+		assert.NotContains(t, sets, marker)
+	})
+}
@@ -0,0 +1,162 @@
+package selectel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// PlanChange is a planned change of an RR set.
+type PlanChange struct {
+	Op  ChangeOp
+	Key RRSetKey
+
+	// Before is the current state of the RR set, nil for ChangeCreate.
+	Before *RRSet
+	// After is the desired state of the RR set, nil for ChangeDelete.
+	After *RRSet
+
+	// exact marks changes computed by DiffAll which restore disabled records of After as well.
+	exact bool
+}
+
+// Plan is a list of RR set changes ordered by RR set key.
+type Plan []PlanChange
+
+// Diff computes changes turning prev RR sets into next ones.
+// RR sets are compared by TTL and enabled records, disabled records in next are ignored.
+// RR sets missing from next are deleted unless they consist of disabled records only.
+func Diff(prev, next map[RRSetKey]*RRSet) Plan {
+	return diff(prev, next, false)
+}
+
+// DiffAll computes changes turning prev RR sets into next ones exactly, disabled records included.
+func DiffAll(prev, next map[RRSetKey]*RRSet) Plan {
+	return diff(prev, next, true)
+}
+
+func diff(prev, next map[RRSetKey]*RRSet, exact bool) Plan {
+	keys := slices.SortedFunc(maps.Keys(overlayRRSets(prev, next)), RRSetKey.Compare)
+	var plan Plan
+	for _, key := range keys {
+		before, after := prev[key], next[key]
+		switch {
+		case before == nil:
+			plan = append(plan, PlanChange{Op: ChangeCreate, Key: key, After: after, exact: exact})
+		case after == nil:
+			if exact || len(before.RRs[enabled]) > 0 {
+				plan = append(plan, PlanChange{Op: ChangeDelete, Key: key, Before: before, exact: exact})
+			}
+		case getTTL(before.TTL) != getTTL(after.TTL) || !before.matchEnabledRRs(after),
+			exact && !maps.Equal(before.RRs[disabled], after.RRs[disabled]):
+			plan = append(plan, PlanChange{Op: ChangeUpdate, Key: key, Before: before, After: after, exact: exact})
+		}
+	}
+
+	return plan
+}
+
+// Apply executes the plan in the zone with client.
+// Disabled records of updated RR sets are kept unless they are enabled by the update
+// (changes computed by DiffAll set disabled records exactly instead).
+// Changes are applied in order, failed changes do not stop the execution.
+func (p Plan) Apply(ctx context.Context, client Client, zone string) (errs error) {
+	for _, change := range p {
+		switch change.Op {
+		case ChangeCreate:
+			set := &RRSet{
+				Key: change.Key,
+				TTL: change.After.TTL,
+				RRs: RRs{maps.Clone(change.After.RRs[enabled])},
+			}
+
+			if change.exact {
+				set.RRs[disabled] = maps.Clone(change.After.RRs[disabled])
+			}
+
+			err := client.CreateRRSet(ctx, zone, set)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", change.Key))
+
+		case ChangeUpdate:
+			set := &RRSet{
+				Key: change.Key,
+				ID:  change.Before.ID,
+				TTL: change.After.TTL,
+				RRs: RRs{maps.Clone(change.After.RRs[enabled]), maps.Clone(change.Before.RRs[disabled])},
+			}
+
+			if change.exact {
+				set.RRs[disabled] = maps.Clone(change.After.RRs[disabled])
+			}
+
+			for data := range set.RRs[enabled] {
+				delete(set.RRs[disabled], data)
+			}
+
+			err := client.UpdateRRSet(ctx, zone, set)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", change.Key))
+
+		case ChangeDelete:
+			err := client.DeleteRRSet(ctx, zone, change.Before.ID)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(err, "delete %s", change.Key))
+		}
+	}
+
+	return
+}
+
+// Format writes the plan to w in zone file format, prefixing removed records with "-"
+// and added records with "+". Records of an updated RR set which are left intact are omitted.
+// Disabled records of changes computed by DiffAll are written as "; disabled:" comments.
+func (p Plan) Format(w io.Writer) error {
+	for _, change := range p {
+		removed := change.formatLines(change.Before)
+		added := change.formatLines(change.After)
+
+		for _, line := range removed {
+			if slices.Contains(added, line) {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "- %s\n", line); err != nil {
+				return err
+			}
+		}
+
+		for _, line := range added {
+			if slices.Contains(removed, line) {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "+ %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c PlanChange) formatLines(set *RRSet) []string {
+	if set == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, data := range sorted(set.RRs[enabled]) {
+		lines = append(lines, formatZoneLine(set, data))
+	}
+
+	if c.exact {
+		for _, data := range sorted(set.RRs[disabled]) {
+			lines = append(lines, "; disabled: "+formatZoneLine(set, data))
+		}
+	}
+
+	return lines
+}
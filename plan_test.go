@@ -0,0 +1,93 @@
+package selectel
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPlan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	prev := map[RRSetKey]*RRSet{
+		{Name: "rrset1", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset1", Type: "A"},
+			ID:  "rrset1",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1", "2.2.2.2"), SetOf("3.3.3.3", "4.4.4.4")},
+		},
+		{Name: "rrset2", Type: "TXT"}: {
+			Key: RRSetKey{Name: "rrset2", Type: "TXT"},
+			ID:  "rrset2",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("hello")},
+		},
+		{Name: "rrset3", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset3", Type: "A"},
+			ID:  "rrset3",
+			TTL: time.Hour,
+			RRs: RRs{nil, SetOf("1.1.1.1")},
+		},
+		{Name: "rrset4", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset4", Type: "A"},
+			ID:  "rrset4",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1")},
+		},
+	}
+
+	next := map[RRSetKey]*RRSet{
+		{Name: "rrset1", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset1", Type: "A"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("2.2.2.2", "3.3.3.3")},
+		},
+		{Name: "rrset4", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset4", Type: "A"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1")},
+		},
+		{Name: "rrset5", Type: "TXT"}: {
+			Key: RRSetKey{Name: "rrset5", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: RRs{SetOf("world")},
+		},
+	}
+
+	plan := Diff(prev, next)
+	require.Len(t, plan, 3)
+	assert.Equal(t, []ChangeOp{ChangeUpdate, ChangeDelete, ChangeCreate}, []ChangeOp{plan[0].Op, plan[1].Op, plan[2].Op})
+
+	var b strings.Builder
+	require.NoError(t, plan.Format(&b))
+	assert.Equal(t, ""+
+		"- rrset1\t3600\tIN\tA\t1.1.1.1\n"+
+		"+ rrset1\t3600\tIN\tA\t3.3.3.3\n"+
+		"- rrset2\t3600\tIN\tTXT\t\"hello\"\n"+
+		"+ rrset5\t60\tIN\tTXT\t\"world\"\n", b.String())
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().UpdateRRSet(ctx, "zone1.org.", &RRSet{
+		Key: RRSetKey{Name: "rrset1", Type: "A"},
+		ID:  "rrset1",
+		TTL: time.Hour,
+		RRs: RRs{SetOf("2.2.2.2", "3.3.3.3"), SetOf("4.4.4.4")},
+	}).Return(nil)
+	client.EXPECT().DeleteRRSet(ctx, "zone1.org.", "rrset2").Return(errors.New("failure"))
+	client.EXPECT().CreateRRSet(ctx, "zone1.org.", &RRSet{
+		Key: RRSetKey{Name: "rrset5", Type: "TXT"},
+		TTL: time.Minute,
+		RRs: RRs{SetOf("world")},
+	}).Return(nil)
+
+	assert.EqualError(t, plan.Apply(ctx, client, "zone1.org."), "delete TXT rrset2: failure")
+	assert.Equal(t, SetOf("3.3.3.3", "4.4.4.4"), prev[RRSetKey{Name: "rrset1", Type: "A"}].RRs[disabled])
+}
@@ -0,0 +1,91 @@
+package selectel
+
+import (
+	"path"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// ErrProtected is returned for RR sets protected by Provider.Protection.
+var ErrProtected = errors.New("RR set is protected")
+
+// ProtectRule matches protected RR sets.
+type ProtectRule struct {
+	// Name is a glob pattern (see path.Match) matched against the name relative to the zone, @ for apex.
+	Name string
+	// Type is a glob pattern matched against the type.
+	Type string
+}
+
+func (r ProtectRule) match(key RRSetKey) bool {
+	nameOK, _ := path.Match(r.Name, key.Name)
+	typeOK, _ := path.Match(r.Type, key.Type)
+	return nameOK && typeOK
+}
+
+// DefaultProtectRules protect apex NS and SOA RR sets.
+var DefaultProtectRules = []ProtectRule{
+	{Name: apex, Type: "NS"},
+	{Name: apex, Type: "SOA"},
+}
+
+// ProtectionPolicy defines RR sets which must not be modified by Provider.
+// The zero value protects RR sets matching DefaultProtectRules and rejects calls touching them.
+type ProtectionPolicy struct {
+	// Rules lists protected RR sets in addition to DefaultProtectRules.
+	Rules []ProtectRule
+	// NoDefaults disables DefaultProtectRules.
+	NoDefaults bool
+	// Skip makes mutation methods skip protected RR sets and proceed with the other ones.
+	// By default the whole call is rejected before any changes are made.
+	Skip bool
+}
+
+func (p ProtectionPolicy) match(key RRSetKey) bool {
+	if !p.NoDefaults {
+		for _, rule := range DefaultProtectRules {
+			if rule.match(key) {
+				return true
+			}
+		}
+	}
+
+	for _, rule := range p.Rules {
+		if rule.match(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipProtected removes protected RR sets from next and reports them in errs.
+// It returns false if the call must be rejected.
+func (p *Provider) skipProtected(next map[RRSetKey]*RRSet, errs *error) bool {
+	protected, ok := p.Protection.check(sortedKeys(next), errs)
+	for key := range protected {
+		delete(next, key)
+	}
+
+	return ok
+}
+
+// check reports protected keys in errs. It returns false if the call must be rejected,
+// otherwise the returned protected keys must be skipped.
+func (p ProtectionPolicy) check(keys []RRSetKey, errs *error) (map[RRSetKey]bool, bool) {
+	var (
+		protected = make(map[RRSetKey]bool)
+		err       error
+	)
+
+	for _, key := range keys {
+		if p.match(key) {
+			protected[key] = true
+			_ = multierr.AppendInto(&err, errors.Wrapf(ErrProtected, "%s", key))
+		}
+	}
+
+	_ = multierr.AppendInto(errs, err)
+	return protected, err == nil || p.Skip
+}
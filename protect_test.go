@@ -0,0 +1,91 @@
+package selectel_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Protection(t *testing.T) {
+	ctx := context.Background()
+	ns := selectel.RRSetKey{Name: "@", Type: "NS"}
+	mx := selectel.RRSetKey{Name: "@", Type: "MX"}
+	www := selectel.RRSetKey{Name: "www", Type: "A"}
+
+	setup := func(t *testing.T) *selecteltest.Client {
+		client := selecteltest.NewClient("zone1.org.")
+		require.NoError(t, client.Put("zone1.org.",
+			&selectel.RRSet{Key: ns, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("ns1.selectel.org."), nil}},
+			&selectel.RRSet{Key: mx, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("10 mx.zone1.org."), nil}},
+			&selectel.RRSet{Key: www, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), nil}},
+		))
+
+		return client
+	}
+
+	nsRecord := libdns.NS{Name: "@", TTL: time.Hour, Target: "ns2.selectel.org."}
+	wwwRecord := libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("2.2.2.2")}
+
+	t.Run("reject by default", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+
+		_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{nsRecord, wwwRecord})
+		assert.ErrorIs(t, err, selectel.ErrProtected)
+
+		_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{libdns.RR{Name: "@"}})
+		assert.ErrorIs(t, err, selectel.ErrProtected)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Equal(t, selectel.SetOf("ns1.selectel.org."), sets[ns].RRs[0])
+		assert.Equal(t, selectel.SetOf("1.1.1.1"), sets[www].RRs[0])
+		assert.Contains(t, sets, mx)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+		provider.Protection = selectel.ProtectionPolicy{
+			Rules: []selectel.ProtectRule{{Name: "@", Type: "MX"}},
+			Skip:  true,
+		}
+
+		_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{nsRecord, wwwRecord})
+		assert.ErrorIs(t, err, selectel.ErrProtected)
+
+		_, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{libdns.RR{Name: "@"}})
+		assert.ErrorIs(t, err, selectel.ErrProtected)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Equal(t, selectel.SetOf("ns1.selectel.org."), sets[ns].RRs[0])
+		assert.Equal(t, selectel.SetOf("2.2.2.2"), sets[www].RRs[0])
+		assert.Contains(t, sets, mx)
+	})
+
+	t.Run("no defaults", func(t *testing.T) {
+		client := setup(t)
+		provider := selectel.NewProvider(client)
+		provider.Protection = selectel.ProtectionPolicy{
+			Rules:      []selectel.ProtectRule{{Name: "w*", Type: "*"}},
+			NoDefaults: true,
+		}
+
+		_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{wwwRecord})
+		assert.ErrorIs(t, err, selectel.ErrProtected)
+
+		_, err = provider.SetRecords(ctx, "zone1.org.", []libdns.Record{nsRecord})
+		require.NoError(t, err)
+
+		sets := client.RRSets("zone1.org.")
+		assert.Equal(t, selectel.SetOf("ns2.selectel.org."), sets[ns].RRs[0])
+		assert.Equal(t, selectel.SetOf("1.1.1.1"), sets[www].RRs[0])
+	})
+}
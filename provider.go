@@ -2,18 +2,65 @@ package selectel
 
 import (
 	"context"
+	"log/slog"
 	"slices"
 	"sync"
 
 	"github.com/libdns/libdns"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 )
 
 // Provider implements libdns.Provider.
+// Records returned by its methods are ordered by name, type, then data,
+// and RR sets are created, updated and deleted in the same order.
 type Provider struct {
 	Credentials
 
+	// TTLPolicy defines how TTL of an RR set is chosen when records disagree.
+	// See TTLPolicy constants for details. TTLMin is used by default.
+	TTLPolicy TTLPolicy
+
+	// DeleteDisabled enables deleting disabled records matching the input in DeleteRecords.
+	DeleteDisabled bool
+
+	// Logger receives record changes at info level, or at error level if a change fails.
+	// It is also passed to the Client created from Credentials. Nothing is logged if it is nil.
+	Logger *slog.Logger
+
+	// TracerProvider is used for creating a span for each method call.
+	// It is also passed to the Client created from Credentials. No spans are created if it is nil.
+	TracerProvider trace.TracerProvider
+
+	// Metrics is passed to the Client created from Credentials. See WithMetrics.
+	Metrics *Metrics
+
+	// Observer is notified about each RR set change, see Observer.
+	Observer Observer
+
+	// Journal records successful RR set changes made by each method call.
+	// Recorded changes can be reverted with Undo.
+	Journal *Journal
+
+	// OwnerID enables ownership mode. In this mode each RR set created by the provider
+	// is accompanied by a TXT marker record (e.g. TXT _libdns-a.www for A www) containing OwnerID,
+	// and RR sets without the marker are neither modified nor deleted (see ErrNotOwned).
+	// Markers are hidden from GetRecords results.
+	OwnerID string
+
+	// ForceOwnership allows modifying and deleting RR sets not owned by OwnerID.
+	// RR sets modified this way are claimed by OwnerID.
+	ForceOwnership bool
+
+	// Protection defines RR sets which must not be modified. Apex NS and SOA RR sets are protected by default.
+	// Protected RR sets are reported with ErrProtected.
+	Protection ProtectionPolicy
+
+	// BlastRadius limits the number of RR sets deleted or replaced by a single call.
+	// Use AllowBulkChanges to override the limits.
+	BlastRadius BlastRadius
+
 	_client Client
 	once    sync.Once
 }
@@ -24,7 +71,10 @@ func NewProvider(client Client) *Provider {
 	return &Provider{_client: client}
 }
 
-func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
+func (p *Provider) ListZones(ctx context.Context) (_ []libdns.Zone, err error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "ListZones")
+	defer func() { endSpan(span, err) }()
+
 	zones, err := p.client().GetZones(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "get zones")
@@ -40,15 +90,19 @@ func (p *Provider) ListZones(ctx context.Context) ([]libdns.Zone, error) {
 	return result, nil
 }
 
-func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+func (p *Provider) GetRecords(ctx context.Context, zone string) (_ []libdns.Record, err error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "GetRecords", zoneKey.String(zone))
+	defer func() { endSpan(span, err) }()
+
 	sets, err := p.client().GetRRSets(ctx, zone)
 	if err != nil {
 		return nil, errors.Wrap(err, "get RR sets")
 	}
 
+	p.hideOwnerMarkers(sets)
 	return slices.Collect(func(yield func(libdns.Record) bool) {
-		for _, set := range sets {
-			for record := range set.toRecords() {
+		for _, key := range sortedKeys(sets) {
+			for record := range sets[key].toRecords() {
 				if !yield(record) {
 					return
 				}
@@ -62,42 +116,68 @@ func (p *Provider) SetRecords(
 	zone string,
 	records []libdns.Record,
 ) (result []libdns.Record, errs error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "SetRecords", zoneKey.String(zone))
+	defer func() { endSpan(span, errs) }()
+
+	var changes []ChangeEvent
+	defer func() { _ = multierr.AppendInto(&errs, p.writeJournal("SetRecords", zone, changes)) }()
+
+	next, err := fromRecords(records, zone, p.TTLPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert records")
+	}
+
 	prev, err := p.client().GetRRSets(ctx, zone)
 	if err != nil {
 		return nil, errors.Wrap(err, "get RR sets")
 	}
 
-	next := fromRecords(records)
+	resolveTTLs(prev, next)
+	if !p.skipProtected(next, &errs) {
+		return nil, errs
+	}
+
+	p.checkOwners(prev, next, &errs)
+	if err := validateRRSets(overlayRRSets(prev, next), next); err != nil {
+		return nil, errors.Wrap(err, "validate records")
+	}
 
+	var replaced int
 	for key, next := range next {
-		if _, ok := prev[key]; ok {
-			continue
+		if prev, ok := prev[key]; ok && (prev.TTL != next.TTL || !prev.matchEnabledRRs(next)) {
+			replaced++
 		}
+	}
 
-		err := p.client().CreateRRSet(ctx, zone, next)
-		if !multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", key)) {
-			result = append(result, slices.Collect(next.toRecords())...)
-		}
+	if err := p.BlastRadius.check(ctx, replaced, len(prev)); err != nil {
+		return nil, multierr.Append(errs, err)
 	}
 
-	for _, prev := range prev {
-		next, ok := next[prev.Key]
+	sets := prev
+	for _, key := range sortedKeys(next) {
+		next := next[key]
+		prev, ok := sets[key]
+		var before *RRSet
 		switch {
 		case !ok:
-			switch {
-			case len(prev.RRs[enabled]) == 0:
+			err := p.client().CreateRRSet(ctx, zone, next)
+			changes = append(changes, p.notify(ctx, ChangeCreate, zone, next, nil, err))
+			if multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", key)) {
 				continue
-			case len(prev.RRs[disabled]) == 0:
-				err := p.client().DeleteRRSet(ctx, zone, prev.ID)
-				_ = multierr.AppendInto(&errs, errors.Wrapf(err, "delete %s", prev.Key))
-				continue
-			default:
-				next = new(RRSet)
 			}
 
-		case prev.TTL != getTTL(prev.TTL, next.TTL) ||
-			!prev.matchEnabledRRs(next):
-			prev.TTL = getTTL(prev.TTL, next.TTL)
+			result = append(result, slices.Collect(next.toRecords())...)
+			if p.OwnerID != "" {
+				event := p.claim(ctx, zone, sets, key)
+				changes = append(changes, event)
+				_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "claim %s", key))
+			}
+
+			continue
+
+		case prev.TTL != next.TTL || !prev.matchEnabledRRs(next):
+			before = prev.clone()
+			prev.TTL = next.TTL
 			prev.RRs[enabled] = next.RRs[enabled]
 			for data := range prev.RRs[enabled] {
 				delete(prev.RRs[disabled], data)
@@ -108,11 +188,19 @@ func (p *Provider) SetRecords(
 		}
 
 		err := p.client().UpdateRRSet(ctx, zone, prev)
-		if !multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
-			result = append(result, slices.Collect(prev.toRecords())...)
+		changes = append(changes, p.notify(ctx, ChangeUpdate, zone, prev, before, err))
+		if multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
+			continue
+		}
+
+		result = append(result, slices.Collect(prev.toRecords())...)
+		if event, ok := p.claimForced(ctx, zone, sets, key); ok {
+			changes = append(changes, event)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "claim %s", key))
 		}
 	}
 
+	slices.SortFunc(result, compareRecords)
 	return
 }
 
@@ -121,97 +209,204 @@ func (p *Provider) AppendRecords(
 	zone string,
 	records []libdns.Record,
 ) (result []libdns.Record, errs error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "AppendRecords", zoneKey.String(zone))
+	defer func() { endSpan(span, errs) }()
+
+	var changes []ChangeEvent
+	defer func() { _ = multierr.AppendInto(&errs, p.writeJournal("AppendRecords", zone, changes)) }()
+
+	next, err := fromRecords(records, zone, p.TTLPolicy)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert records")
+	}
+
 	prev, err := p.client().GetRRSets(ctx, zone)
 	if err != nil {
 		return nil, errors.Wrap(err, "get RR sets")
 	}
 
-	next := fromRecords(records)
+	for _, key := range sortedKeys(next) {
+		next := next[key]
+		prev, ok := prev[key]
+		if !ok {
+			continue
+		}
 
-	for key, next := range next {
+		ttl, err := p.TTLPolicy.apply(prev.TTL, next.TTL)
+		if multierr.AppendInto(&errs, errors.Wrapf(err, "%s", key)) {
+			continue
+		}
+
+		next.TTL = ttl
+	}
+
+	if errs != nil {
+		return nil, errors.Wrap(errs, "validate TTL")
+	}
+
+	resolveTTLs(prev, next)
+
+	if !p.skipProtected(next, &errs) {
+		return nil, errs
+	}
+
+	p.checkOwners(prev, next, &errs)
+	if err := validateRRSets(mergeRRSets(prev, next), next); err != nil {
+		return nil, errors.Wrap(err, "validate records")
+	}
+
+	for _, key := range sortedKeys(next) {
+		next := next[key]
 		if _, ok := prev[key]; ok {
 			continue
 		}
 
 		err := p.client().CreateRRSet(ctx, zone, next)
-		if !multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", key)) {
-			result = append(result, slices.Collect(next.toRecords())...)
+		changes = append(changes, p.notify(ctx, ChangeCreate, zone, next, nil, err))
+		if multierr.AppendInto(&errs, errors.Wrapf(err, "create %s", key)) {
+			continue
+		}
+
+		result = append(result, slices.Collect(next.toRecords())...)
+		if p.OwnerID != "" {
+			event := p.claim(ctx, zone, prev, key)
+			changes = append(changes, event)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "claim %s", key))
 		}
 	}
 
-	for _, prev := range prev {
-		next, ok := next[prev.Key]
+	sets := prev
+	for _, key := range sortedKeys(sets) {
+		prev := sets[key]
+		next, ok := next[key]
 		if !ok {
 			continue
 		}
 
-		var radd []libdns.Record
-		for data := range next.RRs[enabled] {
+		before := prev.clone()
+		var added []string
+		for _, data := range sorted(next.RRs[enabled]) {
 			if prev.RRs[enabled][data] {
 				continue
 			}
 
 			prev.RRs[enabled][data] = true
 			delete(prev.RRs[disabled], data)
+			added = append(added, data)
+		}
+
+		if len(added) == 0 {
+			continue
+		}
 
+		prev.TTL = next.TTL
+
+		err := p.client().UpdateRRSet(ctx, zone, prev)
+		changes = append(changes, p.notify(ctx, ChangeUpdate, zone, prev, before, err))
+		if multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
+			continue
+		}
+
+		for _, data := range added {
 			rr := libdns.RR{
 				Name: prev.Key.Name,
 				Type: prev.Key.Type,
-				TTL:  next.TTL,
+				TTL:  prev.TTL,
 				Data: data,
 			}
 
 			record, err := rr.Parse()
 			if err != nil {
-				radd = append(radd, rr)
+				result = append(result, rr)
 			} else {
-				radd = append(radd, record)
+				result = append(result, record)
 			}
 		}
 
-		if len(radd) == 0 || prev.TTL != next.TTL {
-			continue
-		}
-
-		prev.TTL = next.TTL
-
-		err := p.client().UpdateRRSet(ctx, zone, prev)
-		if !multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
-			result = append(result, radd...)
+		if event, ok := p.claimForced(ctx, zone, sets, key); ok {
+			changes = append(changes, event)
+			_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "claim %s", key))
 		}
 	}
 
+	slices.SortFunc(result, compareRecords)
 	return
 }
 
+// DeleteRecords deletes records matching the provided ones.
+// Record name is always matched (empty name denotes the zone apex),
+// while empty type, zero TTL and empty data match any value.
+// Disabled records are matched only if DeleteDisabled is set.
 func (p *Provider) DeleteRecords(
 	ctx context.Context,
 	zone string,
 	records []libdns.Record,
 ) (result []libdns.Record, errs error) {
+	ctx, span := startSpan(ctx, newTracer(p.TracerProvider), "DeleteRecords", zoneKey.String(zone))
+	defer func() { endSpan(span, errs) }()
+
+	var changes []ChangeEvent
+	defer func() { _ = multierr.AppendInto(&errs, p.writeJournal("DeleteRecords", zone, changes)) }()
+
 	prev, err := p.client().GetRRSets(ctx, zone)
 	if err != nil {
 		return nil, errors.Wrap(err, "get RR sets")
 	}
 
-	next := fromRecords(records)
+	patterns := make([]libdns.RR, len(records))
+	for i, record := range records {
+		patterns[i] = record.RR()
+		patterns[i].Name = normalizeName(patterns[i].Name, zone)
+	}
 
-	for key, prev := range prev {
-		del, ok := next[key]
-		if !ok {
-			key := key
-			key.Type = ""
-			del, ok = next[key]
+	states := []int{enabled}
+	if p.DeleteDisabled {
+		states = append(states, disabled)
+	}
+
+	touched := slices.DeleteFunc(sortedKeys(prev), func(key RRSetKey) bool {
+		if p.OwnerID != "" && isOwnerMarker(key) {
+			return true
 		}
 
-		if !ok {
-			continue
+		set := prev[key]
+		for _, idx := range states {
+			for data := range set.RRs[idx] {
+				if slices.ContainsFunc(patterns, func(rr libdns.RR) bool { return set.matchRecord(data, rr) }) {
+					return false
+				}
+			}
 		}
 
+		return true
+	})
+
+	protected, ok := p.Protection.check(touched, &errs)
+	if !ok {
+		return nil, errs
+	}
+
+	touched = slices.DeleteFunc(touched, func(key RRSetKey) bool {
+		return protected[key] || multierr.AppendInto(&errs, p.checkOwner(prev, key))
+	})
+
+	if err := p.BlastRadius.check(ctx, len(touched), len(prev)); err != nil {
+		return nil, multierr.Append(errs, err)
+	}
+
+	sets := prev
+	for _, key := range touched {
+		prev := sets[key]
+		before := prev.clone()
+
 		var rdel []libdns.Record
-		for data := range prev.RRs[enabled] {
-			if del.RRs[enabled][data] || del.RRs[enabled][""] {
-				delete(prev.RRs[enabled], data)
+		for _, idx := range states {
+			for _, data := range sorted(prev.RRs[idx]) {
+				if !slices.ContainsFunc(patterns, func(rr libdns.RR) bool { return prev.matchRecord(data, rr) }) {
+					continue
+				}
+
+				delete(prev.RRs[idx], data)
 
 				rr := libdns.RR{
 					Name: prev.Key.Name,
@@ -230,25 +425,39 @@ func (p *Provider) DeleteRecords(
 		}
 
 		switch {
-		case len(rdel) == 0:
-			continue
-
 		case len(prev.RRs[enabled]) > 0 || len(prev.RRs[disabled]) > 0:
 			err := p.client().UpdateRRSet(ctx, zone, prev)
-			if !multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
-				result = append(result, rdel...)
+			changes = append(changes, p.notify(ctx, ChangeUpdate, zone, prev, before, err))
+			if multierr.AppendInto(&errs, errors.Wrapf(err, "update %s", prev.Key)) {
+				continue
 			}
 
-			continue
+			result = append(result, rdel...)
+			if event, ok := p.claimForced(ctx, zone, sets, key); ok {
+				changes = append(changes, event)
+				_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "claim %s", key))
+			}
 
 		default:
 			err := p.client().DeleteRRSet(ctx, zone, prev.ID)
-			if !multierr.AppendInto(&errs, errors.Wrapf(err, "delete %s", prev.Key)) {
-				result = append(result, rdel...)
+			changes = append(changes, p.notify(ctx, ChangeDelete, zone, prev, before, err))
+			if multierr.AppendInto(&errs, errors.Wrapf(err, "delete %s", prev.Key)) {
+				continue
+			}
+
+			result = append(result, rdel...)
+			if p.OwnerID == "" {
+				continue
+			}
+
+			if event, ok := p.release(ctx, zone, sets, key); ok {
+				changes = append(changes, event)
+				_ = multierr.AppendInto(&errs, errors.Wrapf(event.Err, "release %s", key))
 			}
 		}
 	}
 
+	slices.SortFunc(result, compareRecords)
 	return
 }
 
@@ -258,12 +467,69 @@ func (p *Provider) client() Client {
 			return
 		}
 
-		p._client = NewClient(p.Credentials)
+		var opts []Option
+		if p.Logger != nil {
+			opts = append(opts, WithLogger(p.Logger))
+		}
+
+		if p.TracerProvider != nil {
+			opts = append(opts, WithTracerProvider(p.TracerProvider))
+		}
+
+		if p.Metrics != nil {
+			opts = append(opts, WithMetrics(p.Metrics))
+		}
+
+		p._client = NewClient(p.Credentials, opts...)
 	})
 
 	return p._client
 }
 
+// notify logs an RR set change at info level (or at error level if it failed) and passes it to Observer.
+// set is the RR set state sent to the API, prev is a copy of the RR set made before the change (nil for ChangeCreate).
+// The returned ChangeEvent is recorded in Journal.
+func (p *Provider) notify(ctx context.Context, op ChangeOp, zone string, set, prev *RRSet, err error) ChangeEvent {
+	if p.Logger != nil {
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelError
+		}
+
+		p.Logger.LogAttrs(ctx, level, op.String()+" RR set",
+			slog.String("zone", zone),
+			slog.String("key", set.Key.String()),
+			slog.Duration("ttl", set.TTL),
+			slog.Any("records", sorted(set.RRs[enabled])),
+			errorAttr(err))
+	}
+
+	event := ChangeEvent{
+		Op:   op,
+		Zone: zone,
+		Key:  set.Key,
+		TTL:  set.TTL,
+		Err:  err,
+	}
+
+	if prev != nil {
+		event.BeforeTTL = prev.TTL
+		event.Before = slices.Collect(prev.toRecords())
+		event.BeforeDisabled = slices.Collect(prev.records(disabled))
+	}
+
+	if op != ChangeDelete {
+		event.After = slices.Collect(set.toRecords())
+		event.AfterDisabled = slices.Collect(set.records(disabled))
+	}
+
+	if p.Observer != nil {
+		p.Observer.OnChange(ctx, event)
+	}
+
+	return event
+}
+
 // type guards
 
 var (
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/multierr"
 )
 
 func TestProvider_ListZones(t *testing.T) {
@@ -114,7 +116,7 @@ func TestProvider_SetRecords(t *testing.T) {
 			},
 		},
 		{
-			Key: RRSetKey{Name: "rrset1", Type: "CNAME"},
+			Key: RRSetKey{Name: "rrset6", Type: "CNAME"},
 			TTL: time.Minute,
 			RRs: RRs{
 				enabled: SetOf("rrset3.zone1.org."),
@@ -138,12 +140,6 @@ func TestProvider_SetRecords(t *testing.T) {
 		client.EXPECT().UpdateRRSet(ctx, "zone1.org.", set).Return(nil)
 	}
 
-	for _, setID := range []string{
-		"rrset2-cname",
-	} {
-		client.EXPECT().DeleteRRSet(ctx, "zone1.org.", setID).Return(nil)
-	}
-
 	provider := NewProvider(client)
 	records, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
 		libdns.Address{
@@ -157,7 +153,7 @@ func TestProvider_SetRecords(t *testing.T) {
 			IP:   netip.AddrFrom4([4]byte{3, 3, 3, 3}),
 		},
 		libdns.CNAME{
-			Name:   "rrset1",
+			Name:   "rrset6",
 			TTL:    time.Minute,
 			Target: "rrset3.zone1.org.",
 		},
@@ -176,7 +172,7 @@ func TestProvider_SetRecords(t *testing.T) {
 	assert.ElementsMatch(t, []libdns.Record{
 		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
 		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{3, 3, 3, 3})},
-		libdns.CNAME{Name: "rrset1", TTL: time.Minute, Target: "rrset3.zone1.org."},
+		libdns.CNAME{Name: "rrset6", TTL: time.Minute, Target: "rrset3.zone1.org."},
 		libdns.TXT{Name: "rrset3", TTL: time.Minute, Text: "HELLO"},
 	}, records)
 }
@@ -379,3 +375,525 @@ func TestProvider_DeleteRecords(t *testing.T) {
 		libdns.TXT{Name: "rrset4", TTL: time.Minute, Text: "HELLO"},
 	}, records)
 }
+
+func TestProvider_SetRecords_TTLPolicy(t *testing.T) {
+	records := []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: 2 * time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+	}
+
+	for _, tc := range []struct {
+		policy TTLPolicy
+		ttl    time.Duration
+	}{
+		{policy: TTLMin, ttl: time.Hour},
+		{policy: TTLMax, ttl: 2 * time.Hour},
+		{policy: TTLFirst, ttl: 2 * time.Hour},
+	} {
+		t.Run(tc.policy.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			client := NewMockClient(ctrl)
+			client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{}, nil)
+			client.EXPECT().CreateRRSet(ctx, "zone1.org.", &RRSet{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				TTL: tc.ttl,
+				RRs: RRs{
+					enabled: SetOf("1.1.1.1", "2.2.2.2"),
+				},
+			}).Return(nil)
+
+			provider := NewProvider(client)
+			provider.TTLPolicy = tc.policy
+			result, err := provider.SetRecords(ctx, "zone1.org.", records)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: tc.ttl, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+				libdns.Address{Name: "rrset1", TTL: tc.ttl, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+			}, result)
+		})
+	}
+}
+
+func TestProvider_SetRecords_TTLStrict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+
+	provider := NewProvider(client)
+	provider.TTLPolicy = TTLStrict
+	_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: 2 * time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+		libdns.TXT{Name: "rrset2", TTL: time.Second, Text: "HELLO"},
+	})
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "A rrset1: conflicting TTLs")
+	assert.ErrorContains(t, err, "TXT rrset2: TTL 1s is out of range")
+}
+
+func TestProvider_AppendRecords_TTLStrict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+		{Name: "rrset1", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset1", Type: "A"},
+			ID:  "rrset1-a",
+			TTL: time.Hour,
+			RRs: RRs{
+				enabled: SetOf("1.1.1.1"),
+			},
+		},
+	}, nil)
+
+	provider := NewProvider(client)
+	provider.TTLPolicy = TTLStrict
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: 2 * time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+	})
+	assert.ErrorContains(t, err, "A rrset1: conflicting TTLs 1h0m0s and 2h0m0s")
+}
+
+func TestProvider_AppendRecords_UnspecifiedTTL(t *testing.T) {
+	for _, policy := range []TTLPolicy{TTLMin, TTLStrict} {
+		t.Run(policy.String(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			client := NewMockClient(ctrl)
+			client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+				{Name: "rrset1", Type: "A"}: {
+					Key: RRSetKey{Name: "rrset1", Type: "A"},
+					ID:  "rrset1-a",
+					TTL: time.Hour,
+					RRs: RRs{
+						enabled:  SetOf("1.1.1.1"),
+						disabled: SetOf[string](),
+					},
+				},
+			}, nil)
+			client.EXPECT().CreateRRSet(ctx, "zone1.org.", &RRSet{
+				Key: RRSetKey{Name: "rrset2", Type: "TXT"},
+				TTL: MinTTL,
+				RRs: RRs{
+					enabled: SetOf("hello"),
+				},
+			}).Return(nil)
+			client.EXPECT().UpdateRRSet(ctx, "zone1.org.", &RRSet{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				ID:  "rrset1-a",
+				TTL: time.Hour,
+				RRs: RRs{
+					enabled:  SetOf("1.1.1.1", "2.2.2.2"),
+					disabled: SetOf[string](),
+				},
+			}).Return(nil)
+
+			provider := NewProvider(client)
+			provider.TTLPolicy = policy
+			result, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+				libdns.Address{Name: "rrset1", IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+				libdns.TXT{Name: "rrset2", Text: "hello"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+				libdns.TXT{Name: "rrset2", TTL: MinTTL, Text: "hello"},
+			}, result)
+		})
+	}
+}
+
+func TestProvider_SetRecords_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{}, nil)
+
+	provider := NewProvider(client)
+	_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.RR{Name: "rrset1", Type: "A", Data: "1.1.1"},
+		libdns.RR{Name: "rrset1", Type: "CNAME", Data: "rrset2.zone1.org."},
+		libdns.RR{Name: "@", Type: "CNAME", Data: "rrset2.zone1.org."},
+		libdns.RR{Name: "@", Type: "MX", Data: "10 1.1.1.1"},
+	})
+
+	var reasons []string
+	for _, err := range multierr.Errors(errors.Cause(err)) {
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		reasons = append(reasons, verr.Reason)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"CNAME at zone apex",
+		"CNAME coexists with other records",
+		"CNAME coexists with other records",
+		"MX record points to IP address",
+		"malformed IP address",
+	}, reasons)
+}
+
+func TestProvider_AppendRecords_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+		{Name: "rrset1", Type: "A"}: {
+			Key: RRSetKey{Name: "rrset1", Type: "A"},
+			ID:  "rrset1-a",
+			TTL: time.Hour,
+			RRs: RRs{
+				enabled: SetOf("1.1.1.1"),
+			},
+		},
+	}, nil)
+
+	provider := NewProvider(client)
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.CNAME{Name: "rrset1", Target: "rrset2.zone1.org."},
+	})
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "CNAME coexists with other records", verr.Reason)
+	assert.ElementsMatch(t, []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+		libdns.CNAME{Name: "rrset1", TTL: time.Minute, Target: "rrset2.zone1.org."},
+	}, verr.Records)
+}
+
+func TestProvider_SetRecords_ValidationUnrelated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+		{Name: "@", Type: "MX"}: {
+			Key: RRSetKey{Name: "@", Type: "MX"},
+			ID:  "apex-mx",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("10 1.1.1.1"), SetOf[string]()},
+		},
+		{Name: "legacy", Type: "A"}: {
+			Key: RRSetKey{Name: "legacy", Type: "A"},
+			ID:  "legacy-a",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("2.2.2.2"), SetOf[string]()},
+		},
+		{Name: "legacy", Type: "CNAME"}: {
+			Key: RRSetKey{Name: "legacy", Type: "CNAME"},
+			ID:  "legacy-cname",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("rrset2.zone1.org."), SetOf[string]()},
+		},
+	}, nil)
+
+	client.EXPECT().CreateRRSet(ctx, "zone1.org.", &RRSet{
+		Key: RRSetKey{Name: "_acme-challenge", Type: "TXT"},
+		TTL: time.Minute,
+		RRs: RRs{SetOf("token"), nil},
+	}).Return(nil)
+
+	provider := NewProvider(client)
+	_, err := provider.SetRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestProvider_AppendRecords_ValidationDisabledCNAME(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			ID:  "www-a",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1"), SetOf[string]()},
+		},
+		{Name: "www", Type: "AAAA"}: {
+			Key: RRSetKey{Name: "www", Type: "AAAA"},
+			ID:  "www-aaaa",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("::1"), SetOf[string]()},
+		},
+		{Name: "www", Type: "CNAME"}: {
+			Key: RRSetKey{Name: "www", Type: "CNAME"},
+			ID:  "www-cname",
+			TTL: time.Hour,
+			RRs: RRs{SetOf[string](), SetOf("rrset2.zone1.org.")},
+		},
+	}, nil)
+
+	client.EXPECT().UpdateRRSet(ctx, "zone1.org.", &RRSet{
+		Key: RRSetKey{Name: "www", Type: "A"},
+		ID:  "www-a",
+		TTL: time.Hour,
+		RRs: RRs{SetOf("1.1.1.1", "2.2.2.2"), SetOf[string]()},
+	}).Return(nil)
+
+	provider := NewProvider(client)
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "www", IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestProvider_DeleteRecords_Apex(t *testing.T) {
+	for _, name := range []string{"@", "", "zone1.org."} {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			client := NewMockClient(ctrl)
+			client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+				{Name: "@", Type: "TXT"}: {
+					Key: RRSetKey{Name: "@", Type: "TXT"},
+					ID:  "apex-txt",
+					TTL: time.Minute,
+					RRs: RRs{
+						enabled: SetOf("HELLO"),
+					},
+				},
+			}, nil)
+			client.EXPECT().DeleteRRSet(ctx, "zone1.org.", "apex-txt").Return(nil)
+
+			provider := NewProvider(client)
+			records, err := provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{
+				libdns.RR{Name: name},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []libdns.Record{
+				libdns.TXT{Name: "@", TTL: time.Minute, Text: "HELLO"},
+			}, records)
+		})
+	}
+}
+
+func TestProvider_AppendRecords_Wildcard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{}, nil)
+	client.EXPECT().CreateRRSet(ctx, "zone1.org.", &RRSet{
+		Key: RRSetKey{Name: "*.sub", Type: "TXT"},
+		TTL: time.Minute,
+		RRs: RRs{
+			enabled: SetOf("HELLO"),
+		},
+	}).Return(nil)
+
+	provider := NewProvider(client)
+	_, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "*.sub", Text: "HELLO"},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "sub.*", Text: "HELLO"},
+	})
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "wildcard is allowed only as the leftmost label", verr.Reason)
+}
+
+func TestProvider_GetRecords_Order(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	client := NewMockClient(ctrl)
+	client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+		{Name: "b", Type: "TXT"}: {
+			Key: RRSetKey{Name: "b", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: RRs{
+				enabled: SetOf("3", "1", "2"),
+			},
+		},
+		{Name: "b", Type: "A"}: {
+			Key: RRSetKey{Name: "b", Type: "A"},
+			TTL: time.Minute,
+			RRs: RRs{
+				enabled: SetOf("2.2.2.2", "1.1.1.1"),
+			},
+		},
+		{Name: "a", Type: "TXT"}: {
+			Key: RRSetKey{Name: "a", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: RRs{
+				enabled: SetOf("HELLO"),
+			},
+		},
+	}, nil)
+
+	provider := NewProvider(client)
+	records, err := provider.GetRecords(ctx, "zone1.org.")
+	require.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.TXT{Name: "a", TTL: time.Minute, Text: "HELLO"},
+		libdns.Address{Name: "b", TTL: time.Minute, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+		libdns.Address{Name: "b", TTL: time.Minute, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+		libdns.TXT{Name: "b", TTL: time.Minute, Text: "1"},
+		libdns.TXT{Name: "b", TTL: time.Minute, Text: "2"},
+		libdns.TXT{Name: "b", TTL: time.Minute, Text: "3"},
+	}, records)
+}
+
+func TestProvider_DeleteRecords_Matching(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		deleteDisabled bool
+		input          libdns.RR
+		deleted        []libdns.Record
+		updated        []*RRSet
+		deletedIDs     []string
+	}{
+		{
+			name:  "name",
+			input: libdns.RR{Name: "rrset1"},
+			deleted: []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+				libdns.TXT{Name: "rrset1", TTL: time.Minute, Text: "HELLO"},
+			},
+			updated: []*RRSet{{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				ID:  "rrset1-a",
+				TTL: time.Hour,
+				RRs: RRs{SetOf[string](), SetOf("2.2.2.2")},
+			}},
+			deletedIDs: []string{"rrset1-txt"},
+		},
+		{
+			name:  "name and type",
+			input: libdns.RR{Name: "rrset1", Type: "TXT"},
+			deleted: []libdns.Record{
+				libdns.TXT{Name: "rrset1", TTL: time.Minute, Text: "HELLO"},
+			},
+			deletedIDs: []string{"rrset1-txt"},
+		},
+		{
+			name:  "name and TTL",
+			input: libdns.RR{Name: "rrset1", TTL: time.Hour},
+			deleted: []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+			},
+			updated: []*RRSet{{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				ID:  "rrset1-a",
+				TTL: time.Hour,
+				RRs: RRs{SetOf[string](), SetOf("2.2.2.2")},
+			}},
+		},
+		{
+			name:  "name and data",
+			input: libdns.RR{Name: "rrset1", Data: "HELLO"},
+			deleted: []libdns.Record{
+				libdns.TXT{Name: "rrset1", TTL: time.Minute, Text: "HELLO"},
+			},
+			deletedIDs: []string{"rrset1-txt"},
+		},
+		{
+			name:  "exact",
+			input: libdns.RR{Name: "rrset1", Type: "A", TTL: time.Hour, Data: "1.1.1.1"},
+			deleted: []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+			},
+			updated: []*RRSet{{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				ID:  "rrset1-a",
+				TTL: time.Hour,
+				RRs: RRs{SetOf[string](), SetOf("2.2.2.2")},
+			}},
+		},
+		{
+			name:  "TTL mismatch",
+			input: libdns.RR{Name: "rrset1", Type: "A", TTL: time.Minute, Data: "1.1.1.1"},
+		},
+		{
+			name:  "other name",
+			input: libdns.RR{Name: "rrset2"},
+		},
+		{
+			name:  "disabled ignored",
+			input: libdns.RR{Name: "rrset1", Data: "2.2.2.2"},
+		},
+		{
+			name:           "disabled",
+			deleteDisabled: true,
+			input:          libdns.RR{Name: "rrset1", Data: "2.2.2.2"},
+			deleted: []libdns.Record{
+				libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+			},
+			updated: []*RRSet{{
+				Key: RRSetKey{Name: "rrset1", Type: "A"},
+				ID:  "rrset1-a",
+				TTL: time.Hour,
+				RRs: RRs{SetOf("1.1.1.1"), SetOf[string]()},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			client := NewMockClient(ctrl)
+			client.EXPECT().GetRRSets(ctx, "zone1.org.").Return(map[RRSetKey]*RRSet{
+				{Name: "rrset1", Type: "A"}: {
+					Key: RRSetKey{Name: "rrset1", Type: "A"},
+					ID:  "rrset1-a",
+					TTL: time.Hour,
+					RRs: RRs{
+						enabled:  SetOf("1.1.1.1"),
+						disabled: SetOf("2.2.2.2"),
+					},
+				},
+				{Name: "rrset1", Type: "TXT"}: {
+					Key: RRSetKey{Name: "rrset1", Type: "TXT"},
+					ID:  "rrset1-txt",
+					TTL: time.Minute,
+					RRs: RRs{
+						enabled: SetOf("HELLO"),
+					},
+				},
+			}, nil)
+
+			for _, set := range tc.updated {
+				client.EXPECT().UpdateRRSet(ctx, "zone1.org.", set).Return(nil)
+			}
+
+			for _, id := range tc.deletedIDs {
+				client.EXPECT().DeleteRRSet(ctx, "zone1.org.", id).Return(nil)
+			}
+
+			provider := NewProvider(client)
+			provider.DeleteDisabled = tc.deleteDisabled
+			records, err := provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{tc.input})
+			require.NoError(t, err)
+			assert.Equal(t, tc.deleted, records)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package selectel_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestProvider_Scenario(t *testing.T) {
+	server := selecteltest.NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+	provider := selectel.NewProvider(selectel.NewClient(testCreds, server.Options()...))
+
+	testScenario(t, provider)
+
+	// the client has to obtain a new token after receiving 401
+	server.ExpireTokens()
+	testScenario(t, provider)
+}
+
+func testScenario(t *testing.T, provider *selectel.Provider) {
+	ctx := context.Background()
+
+	zones, err := provider.ListZones(ctx)
+	require.NoError(t, err)
+	t.Log(zones)
+
+	if len(zones) > 0 {
+		records, err := provider.GetRecords(ctx, zones[0].Name)
+		require.NoError(t, err)
+		t.Log(records)
+
+		record := libdns.TXT{Name: "libdns-integration-test", TTL: time.Minute, Text: `4"5"6`}
+		records, err = provider.AppendRecords(ctx, zones[0].Name, []libdns.Record{record})
+		require.NoError(t, err)
+		t.Log(records)
+		assert.Equal(t, []libdns.Record{record}, records)
+
+		records, err = provider.GetRecords(ctx, zones[0].Name)
+		require.NoError(t, err)
+		assert.Contains(t, records, record)
+
+		records, err = provider.DeleteRecords(ctx, zones[0].Name, []libdns.Record{record})
+		require.NoError(t, err)
+		assert.Equal(t, []libdns.Record{record}, records)
+	}
+}
+
+func TestProvider_Logging(t *testing.T) {
+	server := selecteltest.NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+
+	var tokens []string
+	next := server.Client().Transport
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil && resp.Header.Get("X-Subject-Token") != "" {
+			tokens = append(tokens, resp.Header.Get("X-Subject-Token"))
+		}
+
+		return resp, err
+	})}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	provider := selectel.NewProvider(selectel.NewClient(testCreds,
+		append(server.Options(), selectel.WithHTTPClient(client), selectel.WithLogger(logger))...))
+	provider.Logger = logger
+
+	testScenario(t, provider)
+
+	output := buf.String()
+	assert.Contains(t, output, `level=DEBUG msg="api call" method=ListRRSets status=200`)
+	assert.Contains(t, output, `level=DEBUG msg=GetRRSets zone=zone1.org.`)
+	assert.Contains(t, output, `level=INFO msg="create RR set" zone=zone1.org. key="TXT libdns-integration-test" ttl=1m0s`)
+	assert.Contains(t, output, `level=INFO msg="delete RR set" zone=zone1.org. key="TXT libdns-integration-test"`)
+	assert.NotContains(t, output, testCreds.Password)
+	require.NotEmpty(t, tokens)
+	for _, token := range tokens {
+		assert.NotContains(t, output, token)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestProvider_Tracing(t *testing.T) {
+	server := selecteltest.NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	provider := selectel.NewProvider(selectel.NewClient(testCreds,
+		append(server.Options(), selectel.WithTracerProvider(tracerProvider))...))
+	provider.TracerProvider = tracerProvider
+
+	testScenario(t, provider)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	for _, name := range []string{"ListZones", "GetRecords", "AppendRecords", "DeleteRecords", "GetRRSets", "ListRRSets", "ListZonesPage", "CreateRRSet", "DeleteRRSet", "authorize"} {
+		require.Contains(t, spans, name)
+	}
+
+	assertParent := func(parent, child string) {
+		assert.Equal(t, spans[parent].SpanContext().SpanID(), spans[child].Parent().SpanID(), "parent of %s", child)
+	}
+
+	assertParent("GetRRSets", "ListRRSets")
+	assertParent("AppendRecords", "CreateRRSet")
+	assertParent("DeleteRecords", "DeleteRRSet")
+	assertParent("ListZones", "ListZonesPage")
+	assertParent("ListZonesPage", "authorize")
+
+	assert.Contains(t, spans["CreateRRSet"].Attributes(), attribute.String("selectel.zone", "zone1.org."))
+	assert.Contains(t, spans["CreateRRSet"].Attributes(), attribute.String("selectel.rrset.key", "TXT libdns-integration-test"))
+	assert.Contains(t, spans["CreateRRSet"].Attributes(), attribute.Int("selectel.attempt", 1))
+	assert.Contains(t, spans["CreateRRSet"].Attributes(), attribute.Int("http.response.status_code", 200))
+	assert.Contains(t, spans["authorize"].Attributes(), attribute.Int("http.response.status_code", 201))
+}
+
+func TestProvider_Metrics(t *testing.T) {
+	server := selecteltest.NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+
+	metrics := selectel.NewMetrics("test")
+	provider := selectel.NewProvider(selectel.NewClient(testCreds,
+		append(server.Options(), selectel.WithMetrics(metrics))...))
+
+	testScenario(t, provider)
+	server.ExpireTokens()
+	testScenario(t, provider)
+
+	require.NoError(t, testutil.CollectAndCompare(metrics, strings.NewReader(`
+# HELP test_selectel_authorizations_total Number of token requests by reason (initial, expired or rejected).
+# TYPE test_selectel_authorizations_total counter
+test_selectel_authorizations_total{reason="initial"} 1
+test_selectel_authorizations_total{reason="rejected"} 1
+# HELP test_selectel_requests_total Number of Selectel API requests by endpoint and response status.
+# TYPE test_selectel_requests_total counter
+test_selectel_requests_total{endpoint="CreateRRSet",status="200"} 2
+test_selectel_requests_total{endpoint="DeleteRRSet",status="204"} 2
+test_selectel_requests_total{endpoint="ListRRSets",status="200"} 8
+test_selectel_requests_total{endpoint="ListZones",status="200"} 2
+test_selectel_requests_total{endpoint="ListZones",status="401"} 1
+test_selectel_requests_total{endpoint="authorize",status="201"} 2
+`), "test_selectel_authorizations_total", "test_selectel_requests_total"))
+
+	assert.Equal(t, 5, testutil.CollectAndCount(metrics, "test_selectel_request_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics, "test_selectel_token_expiry_timestamp_seconds"))
+}
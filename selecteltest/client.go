@@ -0,0 +1,247 @@
+// Package selecteltest provides stand-ins for Selectel APIs for use in tests.
+package selecteltest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+// Op is a name of Client operation.
+type Op string
+
+const (
+	OpGetZones    Op = "GetZones"
+	OpGetRRSets   Op = "GetRRSets"
+	OpCreateRRSet Op = "CreateRRSet"
+	OpUpdateRRSet Op = "UpdateRRSet"
+	OpDeleteRRSet Op = "DeleteRRSet"
+)
+
+// FailFunc is called before each Client operation.
+// Returning a non-nil error fails the operation without changing the state.
+// key is empty for zone-wide operations.
+type FailFunc func(op Op, zone string, key selectel.RRSetKey) error
+
+// Client is a stateful in-memory implementation of selectel.Client.
+// It is safe for concurrent use.
+type Client struct {
+	zones  map[string]map[string]*selectel.RRSet
+	fail   FailFunc
+	nextID int
+	mu     sync.Mutex
+}
+
+// NewClient creates a Client with the specified empty zones.
+func NewClient(zones ...string) *Client {
+	c := &Client{zones: make(map[string]map[string]*selectel.RRSet)}
+	for _, zone := range zones {
+		c.AddZone(zone)
+	}
+
+	return c
+}
+
+// AddZone adds an empty zone. Does nothing if the zone already exists.
+func (c *Client) AddZone(zone string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.zones[zone]; !ok {
+		c.zones[zone] = make(map[string]*selectel.RRSet)
+	}
+}
+
+// Put adds or replaces RR sets in the zone bypassing the failure hook.
+// RR sets are matched by key, IDs are assigned to new RR sets.
+func (c *Client) Put(zone string, sets ...*selectel.RRSet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rrsets, ok := c.zones[zone]
+	if !ok {
+		return errors.New("zone not found")
+	}
+
+	for _, set := range sets {
+		set := clone(set)
+		if prev := find(rrsets, set.Key); prev != nil {
+			set.ID = prev.ID
+		} else {
+			set.ID = c.newID()
+		}
+
+		rrsets[set.ID] = set
+	}
+
+	return nil
+}
+
+// RRSets returns a copy of the zone state bypassing the failure hook.
+// nil is returned for unknown zones.
+func (c *Client) RRSets(zone string) map[selectel.RRSetKey]*selectel.RRSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rrsets, ok := c.zones[zone]
+	if !ok {
+		return nil
+	}
+
+	return collect(rrsets)
+}
+
+// SetFail sets the failure hook. Pass nil to disable failures.
+func (c *Client) SetFail(fn FailFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fail = fn
+}
+
+func (c *Client) GetZones(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.check(ctx, OpGetZones, "", selectel.RRSetKey{}); err != nil {
+		return nil, err
+	}
+
+	return slices.Sorted(maps.Keys(c.zones)), nil
+}
+
+func (c *Client) GetRRSets(ctx context.Context, zone string) (map[selectel.RRSetKey]*selectel.RRSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.check(ctx, OpGetRRSets, zone, selectel.RRSetKey{}); err != nil {
+		return nil, err
+	}
+
+	rrsets, ok := c.zones[zone]
+	if !ok {
+		return nil, errors.New("zone not found")
+	}
+
+	return collect(rrsets), nil
+}
+
+func (c *Client) CreateRRSet(ctx context.Context, zone string, set *selectel.RRSet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.check(ctx, OpCreateRRSet, zone, set.Key); err != nil {
+		return err
+	}
+
+	rrsets, ok := c.zones[zone]
+	switch {
+	case !ok:
+		return errors.New("zone not found")
+	case find(rrsets, set.Key) != nil:
+		return errors.Errorf("%s already exists", set.Key)
+	}
+
+	set.ID = c.newID()
+	rrsets[set.ID] = clone(set)
+	return nil
+}
+
+func (c *Client) UpdateRRSet(ctx context.Context, zone string, set *selectel.RRSet) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.check(ctx, OpUpdateRRSet, zone, set.Key); err != nil {
+		return err
+	}
+
+	rrsets, ok := c.zones[zone]
+	if !ok {
+		return errors.New("zone not found")
+	}
+
+	prev, ok := rrsets[set.ID]
+	switch {
+	case !ok:
+		return errors.Errorf("RR set %s not found", set.ID)
+	case prev.Key != set.Key:
+		return errors.Errorf("RR set %s key mismatch: %s != %s", set.ID, prev.Key, set.Key)
+	}
+
+	rrsets[set.ID] = clone(set)
+	return nil
+}
+
+func (c *Client) DeleteRRSet(ctx context.Context, zone string, setID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rrsets, ok := c.zones[zone]
+	if !ok {
+		return errors.New("zone not found")
+	}
+
+	set, ok := rrsets[setID]
+	if !ok {
+		return errors.Errorf("RR set %s not found", setID)
+	}
+
+	if err := c.check(ctx, OpDeleteRRSet, zone, set.Key); err != nil {
+		return err
+	}
+
+	delete(rrsets, setID)
+	return nil
+}
+
+func (c *Client) check(ctx context.Context, op Op, zone string, key selectel.RRSetKey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if c.fail != nil {
+		return c.fail(op, zone, key)
+	}
+
+	return nil
+}
+
+func (c *Client) newID() string {
+	c.nextID++
+	return fmt.Sprintf("rrset-%d", c.nextID)
+}
+
+func find(rrsets map[string]*selectel.RRSet, key selectel.RRSetKey) *selectel.RRSet {
+	for _, set := range rrsets {
+		if set.Key == key {
+			return set
+		}
+	}
+
+	return nil
+}
+
+func collect(rrsets map[string]*selectel.RRSet) map[selectel.RRSetKey]*selectel.RRSet {
+	result := make(map[selectel.RRSetKey]*selectel.RRSet, len(rrsets))
+	for _, set := range rrsets {
+		result[set.Key] = clone(set)
+	}
+
+	return result
+}
+
+func clone(set *selectel.RRSet) *selectel.RRSet {
+	result := *set
+	for idx := range result.RRs {
+		result.RRs[idx] = maps.Clone(result.RRs[idx])
+	}
+
+	return &result
+}
+
+var _ selectel.Client = (*Client)(nil)
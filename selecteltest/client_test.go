@@ -0,0 +1,92 @@
+package selecteltest
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+func TestClient_Provider(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.", &selectel.RRSet{
+		Key: selectel.RRSetKey{Name: "rrset1", Type: "A"},
+		TTL: time.Hour,
+		RRs: selectel.RRs{
+			selectel.SetOf("1.1.1.1"),
+			selectel.SetOf("2.2.2.2"),
+		},
+	}))
+
+	provider := selectel.NewProvider(client)
+
+	zones, err := provider.ListZones(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []libdns.Zone{{Name: "zone1.org."}}, zones)
+
+	records, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+		libdns.TXT{Name: "rrset2", Text: "HELLO"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+		libdns.TXT{Name: "rrset2", TTL: time.Minute, Text: "HELLO"},
+	}, records)
+
+	records, err = provider.DeleteRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.RR{Name: "rrset1", Data: "1.1.1.1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+	}, records)
+
+	records, err = provider.GetRecords(ctx, "zone1.org.")
+	require.NoError(t, err)
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Hour, IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+		libdns.TXT{Name: "rrset2", TTL: time.Minute, Text: "HELLO"},
+	}, records)
+
+	sets := client.RRSets("zone1.org.")
+	assert.Len(t, sets, 2)
+	assert.NotEmpty(t, sets[selectel.RRSetKey{Name: "rrset2", Type: "TXT"}].ID)
+}
+
+func TestClient_Fail(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("zone1.org.")
+	client.SetFail(func(op Op, zone string, key selectel.RRSetKey) error {
+		if op == OpCreateRRSet && key.Type == "TXT" {
+			return errors.New("injected")
+		}
+
+		return nil
+	})
+
+	provider := selectel.NewProvider(client)
+	records, err := provider.AppendRecords(ctx, "zone1.org.", []libdns.Record{
+		libdns.Address{Name: "rrset1", IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+		libdns.TXT{Name: "rrset2", Text: "HELLO"},
+	})
+	assert.ErrorContains(t, err, "create TXT rrset2: injected")
+	assert.Equal(t, []libdns.Record{
+		libdns.Address{Name: "rrset1", TTL: time.Minute, IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+	}, records)
+	assert.Len(t, client.RRSets("zone1.org."), 1)
+}
+
+func TestClient_Conformance(t *testing.T) {
+	RunConformance(t, func(t *testing.T) (Provider, string) {
+		return selectel.NewProvider(NewClient("zone1.org.")), "zone1.org."
+	})
+}
@@ -0,0 +1,232 @@
+package selecteltest
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Provider is the set of libdns interfaces checked by RunConformance.
+type Provider interface {
+	libdns.RecordGetter
+	libdns.RecordAppender
+	libdns.RecordSetter
+	libdns.RecordDeleter
+}
+
+// SetupFunc returns a provider and a name of an empty zone managed by it.
+// It is called once per conformance test case.
+type SetupFunc func(t *testing.T) (provider Provider, zone string)
+
+// RunConformance checks that the provider follows libdns semantics of
+// GetRecords, AppendRecords, SetRecords and DeleteRecords.
+func RunConformance(t *testing.T, setup SetupFunc) {
+	t.Run("RoundTrip", func(t *testing.T) { testRoundTrip(t, setup) })
+	t.Run("AppendIdempotent", func(t *testing.T) { testAppendIdempotent(t, setup) })
+	t.Run("SetIdempotent", func(t *testing.T) { testSetIdempotent(t, setup) })
+	t.Run("SetReplacesOnlyInputRRSets", func(t *testing.T) { testSetReplacesOnlyInputRRSets(t, setup) })
+	t.Run("TTL", func(t *testing.T) { testTTL(t, setup) })
+	t.Run("DeleteWildcards", func(t *testing.T) { testDeleteWildcards(t, setup) })
+}
+
+func testRecords(zone string) []libdns.Record {
+	return []libdns.Record{
+		libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("2001:db8::1")},
+		libdns.CNAME{Name: "alias", TTL: time.Hour, Target: "www." + zone},
+		libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mail." + zone},
+		libdns.TXT{Name: "txt", TTL: time.Hour, Text: `hello "world"`},
+		libdns.NS{Name: "sub", TTL: time.Hour, Target: "ns1.example.net."},
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "sip", TTL: time.Hour, Priority: 10, Weight: 20, Port: 5060, Target: "sip." + zone},
+		libdns.CAA{Name: "@", TTL: time.Hour, Flags: 0, Tag: "issue", Value: "letsencrypt.org"},
+	}
+}
+
+func testRoundTrip(t *testing.T, setup SetupFunc) {
+	ctx := context.Background()
+	provider, zone := setup(t)
+
+	for _, record := range testRecords(zone) {
+		rr := record.RR()
+		t.Run(rr.Type, func(t *testing.T) {
+			added, err := provider.AppendRecords(ctx, zone, []libdns.Record{record})
+			require.NoError(t, err)
+			assertRecords(t, []libdns.Record{record}, added)
+
+			records, err := provider.GetRecords(ctx, zone)
+			require.NoError(t, err)
+			assert.Contains(t, rrs(records), rr)
+
+			deleted, err := provider.DeleteRecords(ctx, zone, []libdns.Record{record})
+			require.NoError(t, err)
+			assertRecords(t, []libdns.Record{record}, deleted)
+
+			records, err = provider.GetRecords(ctx, zone)
+			require.NoError(t, err)
+			assert.NotContains(t, rrs(records), rr)
+		})
+	}
+}
+
+func testAppendIdempotent(t *testing.T, setup SetupFunc) {
+	ctx := context.Background()
+	provider, zone := setup(t)
+	input := testRecords(zone)
+
+	added, err := provider.AppendRecords(ctx, zone, input)
+	require.NoError(t, err)
+	assertRecords(t, input, added)
+
+	added, err = provider.AppendRecords(ctx, zone, input)
+	require.NoError(t, err)
+	assert.Empty(t, added)
+
+	records, err := provider.GetRecords(ctx, zone)
+	require.NoError(t, err)
+	assertRecords(t, input, records)
+}
+
+func testSetIdempotent(t *testing.T, setup SetupFunc) {
+	ctx := context.Background()
+	provider, zone := setup(t)
+	input := testRecords(zone)
+
+	set, err := provider.SetRecords(ctx, zone, input)
+	require.NoError(t, err)
+	assertRecords(t, input, set)
+
+	_, err = provider.SetRecords(ctx, zone, input)
+	require.NoError(t, err)
+
+	records, err := provider.GetRecords(ctx, zone)
+	require.NoError(t, err)
+	assertRecords(t, input, records)
+}
+
+func testSetReplacesOnlyInputRRSets(t *testing.T, setup SetupFunc) {
+	ctx := context.Background()
+	provider, zone := setup(t)
+
+	_, err := provider.AppendRecords(ctx, zone, []libdns.Record{
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.2")},
+		libdns.TXT{Name: "alpha", TTL: time.Hour, Text: "hello world"},
+		libdns.Address{Name: "beta", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.3")},
+	})
+	require.NoError(t, err)
+
+	_, err = provider.SetRecords(ctx, zone, []libdns.Record{
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.2")},
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.4")},
+	})
+	require.NoError(t, err)
+
+	records, err := provider.GetRecords(ctx, zone)
+	require.NoError(t, err)
+	assertRecords(t, []libdns.Record{
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.2")},
+		libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.4")},
+		libdns.TXT{Name: "alpha", TTL: time.Hour, Text: "hello world"},
+		libdns.Address{Name: "beta", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.3")},
+	}, records)
+}
+
+func testTTL(t *testing.T, setup SetupFunc) {
+	ctx := context.Background()
+	provider, zone := setup(t)
+
+	_, err := provider.AppendRecords(ctx, zone, []libdns.Record{
+		libdns.TXT{Name: "ttl", TTL: time.Hour, Text: "hello"},
+	})
+	require.NoError(t, err)
+
+	set, err := provider.SetRecords(ctx, zone, []libdns.Record{
+		libdns.TXT{Name: "ttl", TTL: 2 * time.Hour, Text: "hello"},
+	})
+	require.NoError(t, err)
+	assertRecords(t, []libdns.Record{
+		libdns.TXT{Name: "ttl", TTL: 2 * time.Hour, Text: "hello"},
+	}, set)
+
+	records, err := provider.GetRecords(ctx, zone)
+	require.NoError(t, err)
+	assertRecords(t, []libdns.Record{
+		libdns.TXT{Name: "ttl", TTL: 2 * time.Hour, Text: "hello"},
+	}, records)
+
+	deleted, err := provider.DeleteRecords(ctx, zone, []libdns.Record{
+		libdns.TXT{Name: "ttl", TTL: time.Hour, Text: "hello"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, deleted, "TTL mismatch")
+}
+
+func testDeleteWildcards(t *testing.T, setup SetupFunc) {
+	for _, tc := range []struct {
+		name    string
+		input   libdns.RR
+		deleted []string
+	}{
+		{name: "Name", input: libdns.RR{Name: "alpha"}, deleted: []string{"alpha A 192.0.2.1", "alpha A 192.0.2.2", "alpha TXT hello"}},
+		{name: "NameType", input: libdns.RR{Name: "alpha", Type: "A"}, deleted: []string{"alpha A 192.0.2.1", "alpha A 192.0.2.2"}},
+		{name: "NameData", input: libdns.RR{Name: "alpha", Data: "192.0.2.2"}, deleted: []string{"alpha A 192.0.2.2"}},
+		{name: "NameTTL", input: libdns.RR{Name: "alpha", TTL: 2 * time.Hour}, deleted: []string{"alpha TXT hello"}},
+		{name: "Exact", input: libdns.RR{Name: "alpha", Type: "A", TTL: time.Hour, Data: "192.0.2.1"}, deleted: []string{"alpha A 192.0.2.1"}},
+		{name: "Missing", input: libdns.RR{Name: "gamma"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider, zone := setup(t)
+
+			input := []libdns.Record{
+				libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+				libdns.Address{Name: "alpha", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.2")},
+				libdns.TXT{Name: "alpha", TTL: 2 * time.Hour, Text: "hello"},
+				libdns.Address{Name: "beta", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+			}
+
+			_, err := provider.AppendRecords(ctx, zone, input)
+			require.NoError(t, err)
+
+			deleted, err := provider.DeleteRecords(ctx, zone, []libdns.Record{tc.input})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.deleted, keys(deleted))
+
+			records, err := provider.GetRecords(ctx, zone)
+			require.NoError(t, err)
+			remaining := slices.DeleteFunc(keys(input), func(key string) bool { return slices.Contains(tc.deleted, key) })
+			assert.ElementsMatch(t, remaining, keys(records))
+		})
+	}
+}
+
+func assertRecords(t *testing.T, expected, actual []libdns.Record) {
+	t.Helper()
+	assert.ElementsMatch(t, rrs(expected), rrs(actual))
+}
+
+func rrs(records []libdns.Record) []libdns.RR {
+	result := make([]libdns.RR, len(records))
+	for i, record := range records {
+		result[i] = record.RR()
+	}
+
+	return result
+}
+
+func keys(records []libdns.Record) []string {
+	result := make([]string, len(records))
+	for i, record := range records {
+		rr := record.RR()
+		result[i] = strings.Join([]string{rr.Name, rr.Type, rr.Data}, " ")
+	}
+
+	return result
+}
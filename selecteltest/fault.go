@@ -0,0 +1,143 @@
+package selecteltest
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault describes a failure injected into a single request by FaultTransport.
+type Fault struct {
+	// Latency delays the request. The delay is interrupted if the request context is done.
+	Latency time.Duration
+	// StatusCode makes the transport respond with the status instead of forwarding the request.
+	StatusCode int
+	// RetryAfter sets Retry-After header (in seconds) of the injected response.
+	RetryAfter time.Duration
+	// Reset makes the request fail with a connection reset error.
+	Reset bool
+}
+
+// ExpiredToken is a fault imitating an API response to a request with an expired token.
+var ExpiredToken = Fault{StatusCode: http.StatusUnauthorized}
+
+// FaultFunc returns the fault to inject into the request, nil for none.
+// n is the number of the request (starting from 1) passed through the transport.
+type FaultFunc func(req *http.Request, n int) *Fault
+
+// FaultTransport is a http.RoundTripper injecting faults into requests passed to Next.
+// It can be used with selectel.WithHTTPClient.
+type FaultTransport struct {
+	// Next handles requests without injected StatusCode or Reset faults.
+	// http.DefaultTransport is used if nil.
+	Next http.RoundTripper
+	// Fault selects faults for requests.
+	Fault FaultFunc
+
+	requests []string
+	mu       sync.Mutex
+}
+
+// Requests returns method and path of requests passed through the transport so far.
+func (t *FaultTransport) Requests() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.requests...)
+}
+
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests = append(t.requests, req.Method+" "+req.URL.Path)
+	n := len(t.requests)
+	t.mu.Unlock()
+
+	var fault *Fault
+	if t.Fault != nil {
+		fault = t.Fault(req, n)
+	}
+
+	if fault == nil {
+		return t.next().RoundTrip(req)
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if fault.Reset || fault.StatusCode != 0 {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+	}
+
+	switch {
+	case fault.Reset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	case fault.StatusCode != 0:
+		body := []byte(`{"error":"injected fault"}`)
+		header := make(http.Header)
+		header.Set("Content-Type", "application/json")
+		if fault.RetryAfter > 0 {
+			header.Set("Retry-After", strconv.Itoa(int(fault.RetryAfter.Seconds())))
+		}
+
+		return &http.Response{
+			Status:        http.StatusText(fault.StatusCode),
+			StatusCode:    fault.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+
+	default:
+		return t.next().RoundTrip(req)
+	}
+}
+
+func (t *FaultTransport) next() http.RoundTripper {
+	if t.Next != nil {
+		return t.Next
+	}
+
+	return http.DefaultTransport
+}
+
+// FailFirst returns a FaultFunc injecting the fault into the first n requests matching the predicate.
+// All requests match if the predicate is nil.
+func FailFirst(n int, fault Fault, match func(req *http.Request) bool) FaultFunc {
+	var (
+		count int
+		mu    sync.Mutex
+	)
+
+	return func(req *http.Request, _ int) *Fault {
+		if match != nil && !match(req) {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if count >= n {
+			return nil
+		}
+
+		count++
+		return &fault
+	}
+}
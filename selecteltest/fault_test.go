@@ -0,0 +1,109 @@
+package selecteltest
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+const (
+	authRequest  = "POST " + authPath
+	zonesRequest = "GET " + apiPath + "/zones"
+)
+
+func TestFaultTransport(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		fault    FaultFunc
+		timeout  time.Duration
+		err      string
+		requests []string
+		elapsed  time.Duration
+	}{
+		{
+			name:     "5xx",
+			fault:    FailFirst(2, Fault{StatusCode: http.StatusServiceUnavailable}, isAPI),
+			requests: []string{authRequest, zonesRequest, zonesRequest, zonesRequest},
+		},
+		{
+			name:     "5xx exhausted",
+			fault:    FailFirst(3, Fault{StatusCode: http.StatusServiceUnavailable}, isAPI),
+			err:      "injected fault",
+			requests: []string{authRequest, zonesRequest, zonesRequest, zonesRequest},
+		},
+		{
+			name:     "429 with Retry-After",
+			fault:    FailFirst(1, Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second}, isAPI),
+			requests: []string{authRequest, zonesRequest, zonesRequest},
+			elapsed:  time.Second,
+		},
+		{
+			name:     "connection reset",
+			fault:    FailFirst(1, Fault{Reset: true}, nil),
+			requests: []string{authRequest, authRequest, zonesRequest},
+		},
+		{
+			name:     "expired token",
+			fault:    FailFirst(1, ExpiredToken, isAPI),
+			requests: []string{authRequest, zonesRequest, authRequest, zonesRequest},
+		},
+		{
+			name:     "persistent token rejection",
+			fault:    FailFirst(1000, ExpiredToken, isAPI),
+			err:      "token rejected after re-authorization",
+			requests: []string{authRequest, zonesRequest, authRequest, zonesRequest},
+		},
+		{
+			name:     "latency",
+			fault:    FailFirst(1, Fault{Latency: time.Minute}, isAPI),
+			timeout:  100 * time.Millisecond,
+			err:      context.DeadlineExceeded.Error(),
+			requests: []string{authRequest, zonesRequest},
+		},
+		{
+			name:     "invalid credentials",
+			fault:    FailFirst(1, Fault{StatusCode: http.StatusUnauthorized}, nil),
+			err:      "invalid credentials",
+			requests: []string{authRequest},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer(testCreds)
+			defer server.Close()
+
+			server.AddZone("zone1.org.")
+			transport := &FaultTransport{Next: server.Client().Transport, Fault: tc.fault}
+			client := selectel.NewClient(testCreds, append(server.Options(), selectel.WithHTTPClient(&http.Client{Transport: transport}))...)
+
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			startedAt := time.Now()
+			zones, err := client.GetZones(ctx)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, []string{"zone1.org."}, zones)
+			}
+
+			assert.Equal(t, tc.requests, transport.Requests())
+			assert.GreaterOrEqual(t, time.Since(startedAt), tc.elapsed)
+		})
+	}
+}
+
+func isAPI(req *http.Request) bool {
+	return strings.HasPrefix(req.URL.Path, apiPath)
+}
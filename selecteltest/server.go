@@ -0,0 +1,379 @@
+package selecteltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v2 "github.com/selectel/domains-go/pkg/v2"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+const (
+	authPath = "/identity/v3/auth/tokens"
+	apiPath  = "/domains/v2"
+)
+
+// Server is a local stand-in for Selectel Keystone token endpoint and Domains API v2
+// implementing the subset of endpoints used by selectel.Client.
+type Server struct {
+	*httptest.Server
+
+	creds    selectel.Credentials
+	tokenTTL time.Duration
+	tokens   map[string]time.Time
+	zones    map[string]*zone
+	fail     func(r *http.Request) int
+	nextID   int
+	mu       sync.Mutex
+}
+
+type zone struct {
+	v2.Zone
+	rrsets map[string]*v2.RRSet
+}
+
+// NewServer starts a Server accepting the provided credentials.
+// It should be closed with Close after use.
+func NewServer(creds selectel.Credentials) *Server {
+	s := &Server{
+		creds:    creds,
+		tokenTTL: 24 * time.Hour,
+		tokens:   make(map[string]time.Time),
+		zones:    make(map[string]*zone),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+authPath, s.authorize)
+	mux.HandleFunc("GET "+apiPath+"/zones", s.authenticated(s.listZones))
+	mux.HandleFunc("GET "+apiPath+"/zones/{zoneID}/rrset", s.authenticated(s.listRRSets))
+	mux.HandleFunc("POST "+apiPath+"/zones/{zoneID}/rrset", s.authenticated(s.createRRSet))
+	mux.HandleFunc("PATCH "+apiPath+"/zones/{zoneID}/rrset/{rrsetID}", s.authenticated(s.updateRRSet))
+	mux.HandleFunc("DELETE "+apiPath+"/zones/{zoneID}/rrset/{rrsetID}", s.authenticated(s.deleteRRSet))
+
+	s.Server = httptest.NewServer(s.intercept(mux))
+	return s
+}
+
+// Options returns client options directing requests to the server.
+func (s *Server) Options() []selectel.Option {
+	return []selectel.Option{
+		selectel.WithAuthURL(s.AuthURL()),
+		selectel.WithAPIURL(s.APIURL()),
+		selectel.WithHTTPClient(s.Client()),
+	}
+}
+
+// AuthURL returns the token endpoint URL.
+func (s *Server) AuthURL() string {
+	return s.URL + authPath
+}
+
+// APIURL returns the base URL of Domains API.
+func (s *Server) APIURL() string {
+	return s.URL + apiPath
+}
+
+// AddZone creates an empty zone and returns its ID.
+// zone name should be fully-qualified.
+func (s *Server) AddZone(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.newID()
+	s.zones[id] = &zone{
+		Zone:   v2.Zone{ID: id, Name: name, CreatedAt: time.Now()},
+		rrsets: make(map[string]*v2.RRSet),
+	}
+
+	return id
+}
+
+// RRSets returns a copy of RR sets in the zone with the specified name.
+func (s *Server) RRSets(name string) []v2.RRSet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []v2.RRSet
+	for _, zone := range s.zones {
+		if zone.Name != name {
+			continue
+		}
+
+		for _, rrset := range zone.sortedRRSets() {
+			rrset := *rrset
+			rrset.Records = slices.Clone(rrset.Records)
+			result = append(result, rrset)
+		}
+	}
+
+	return result
+}
+
+// SetTokenTTL sets the lifetime of tokens issued after the call.
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenTTL = ttl
+}
+
+// ExpireTokens invalidates all issued tokens, so that subsequent API requests using them get 401.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.tokens)
+}
+
+// SetFail sets the failure hook called before handling each request.
+// Returning a non-zero status code fails the request with it. Pass nil to disable failures.
+func (s *Server) SetFail(fn func(r *http.Request) int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fn
+}
+
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		fail := s.fail
+		s.mu.Unlock()
+
+		if fail != nil {
+			if code := fail(r); code != 0 {
+				writeError(w, code, "injected failure")
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Auth struct {
+			Identity struct {
+				Password struct {
+					User struct {
+						Name   string `json:"name"`
+						Domain struct {
+							Name string `json:"name"`
+						} `json:"domain"`
+						Password string `json:"password"`
+					} `json:"user"`
+				} `json:"password"`
+			} `json:"identity"`
+			Scope struct {
+				Project struct {
+					Name string `json:"name"`
+				} `json:"project"`
+			} `json:"scope"`
+		} `json:"auth"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user := in.Auth.Identity.Password.User
+	if user.Name != s.creds.Username ||
+		user.Password != s.creds.Password ||
+		user.Domain.Name != s.creds.AccountID ||
+		in.Auth.Scope.Project.Name != s.creds.ProjectName {
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	s.mu.Lock()
+	token := newToken()
+	issuedAt := time.Now().UTC()
+	expiresAt := issuedAt.Add(s.tokenTTL)
+	s.tokens[token] = expiresAt
+	s.mu.Unlock()
+
+	var out struct {
+		Token struct {
+			ExpiresAt time.Time `json:"expires_at"`
+			IssuedAt  time.Time `json:"issued_at"`
+		} `json:"token"`
+	}
+
+	out.Token.IssuedAt = issuedAt
+	out.Token.ExpiresAt = expiresAt
+
+	w.Header().Set("X-Subject-Token", token)
+	writeJSON(w, http.StatusCreated, out)
+}
+
+func (s *Server) authenticated(next func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		expiresAt, ok := s.tokens[r.Header.Get("X-Auth-Token")]
+		s.mu.Unlock()
+
+		if !ok || expiresAt.Before(time.Now()) {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next(w, r)
+	}
+}
+
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request) {
+	filter := r.URL.Query().Get("filter")
+	zones := slices.SortedFunc(maps.Values(s.zones), func(a, b *zone) int { return strings.Compare(a.Name, b.Name) })
+	var items []*v2.Zone
+	for _, zone := range zones {
+		if strings.Contains(zone.Name, filter) {
+			items = append(items, &zone.Zone)
+		}
+	}
+
+	writePage(w, r, items)
+}
+
+func (s *Server) listRRSets(w http.ResponseWriter, r *http.Request) {
+	zone, ok := s.zones[r.PathValue("zoneID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	writePage(w, r, zone.sortedRRSets())
+}
+
+func (s *Server) createRRSet(w http.ResponseWriter, r *http.Request) {
+	zone, ok := s.zones[r.PathValue("zoneID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	var rrset v2.RRSet
+	if err := json.NewDecoder(r.Body).Decode(&rrset); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !strings.HasSuffix(rrset.Name, zone.Name) {
+		writeError(w, http.StatusBadRequest, "name is outside of the zone")
+		return
+	}
+
+	for _, other := range zone.rrsets {
+		if other.Name == rrset.Name && other.Type == rrset.Type {
+			writeError(w, http.StatusConflict, "rrset already exists")
+			return
+		}
+	}
+
+	rrset.ID = s.newID()
+	rrset.ZoneID = zone.ID
+	zone.rrsets[rrset.ID] = &rrset
+
+	writeJSON(w, http.StatusOK, rrset)
+}
+
+func (s *Server) updateRRSet(w http.ResponseWriter, r *http.Request) {
+	zone, ok := s.zones[r.PathValue("zoneID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	rrset, ok := zone.rrsets[r.PathValue("rrsetID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "rrset not found")
+		return
+	}
+
+	var form struct {
+		TTL     int             `json:"ttl"`
+		Records []v2.RecordItem `json:"records"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rrset.TTL = form.TTL
+	rrset.Records = form.Records
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteRRSet(w http.ResponseWriter, r *http.Request) {
+	zone, ok := s.zones[r.PathValue("zoneID")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	rrsetID := r.PathValue("rrsetID")
+	if _, ok := zone.rrsets[rrsetID]; !ok {
+		writeError(w, http.StatusNotFound, "rrset not found")
+		return
+	}
+
+	delete(zone.rrsets, rrsetID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%08d-0000-0000-0000-000000000000", s.nextID)
+}
+
+func (z *zone) sortedRRSets() []*v2.RRSet {
+	return slices.SortedFunc(maps.Values(z.rrsets), func(a, b *v2.RRSet) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+func writePage[T v2.Zone | v2.RRSet](w http.ResponseWriter, r *http.Request, items []*T) {
+	query := r.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = len(items)
+	}
+
+	offset = min(offset, len(items))
+	end := min(offset+limit, len(items))
+	writeJSON(w, http.StatusOK, v2.List[T]{
+		Count:      end - offset,
+		NextOffset: end,
+		Items:      items[offset:end],
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, v2.BadResponseError{ErrorMsg: message, Code: code})
+}
+
+func newToken() string {
+	data := make([]byte, 16)
+	_, _ = rand.Read(data)
+	return hex.EncodeToString(data)
+}
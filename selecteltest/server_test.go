@@ -0,0 +1,115 @@
+package selecteltest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	v2 "github.com/selectel/domains-go/pkg/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+)
+
+var testCreds = selectel.Credentials{
+	Username:    "user",
+	Password:    "password",
+	AccountID:   "123456",
+	ProjectName: "project",
+}
+
+func TestServer_Pagination(t *testing.T) {
+	server := NewServer(testCreds)
+	defer server.Close()
+
+	zoneID := server.AddZone("zone1.org.")
+	token := issueToken(t, server)
+
+	headers := make(http.Header)
+	headers.Set("X-Auth-Token", token)
+	dns := v2.NewClient(server.URL+apiPath, server.Client(), headers)
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := dns.CreateRRSet(ctx, zoneID, &v2.RRSet{
+			Name:    name + ".zone1.org.",
+			Type:    "TXT",
+			TTL:     60,
+			Records: []v2.RecordItem{{Content: `"HELLO"`}},
+		})
+		require.NoError(t, err)
+	}
+
+	page, err := dns.ListRRSets(ctx, zoneID, &map[string]string{"offset": "0", "limit": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.GetCount())
+	assert.Equal(t, 2, page.GetNextOffset())
+
+	page, err = dns.ListRRSets(ctx, zoneID, &map[string]string{"offset": "2", "limit": "2"})
+	require.NoError(t, err)
+	require.Equal(t, 1, page.GetCount())
+	assert.Equal(t, "c.zone1.org.", page.GetItems()[0].Name)
+}
+
+func TestServer_ExpireTokens(t *testing.T) {
+	server := NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+	token := issueToken(t, server)
+	server.ExpireTokens()
+
+	headers := make(http.Header)
+	headers.Set("X-Auth-Token", token)
+	dns := v2.NewClient(server.URL+apiPath, server.Client(), headers)
+
+	_, err := dns.ListZones(context.Background(), nil)
+	var bad *v2.BadResponseError
+	require.ErrorAs(t, err, &bad)
+	assert.Equal(t, http.StatusUnauthorized, bad.Code)
+}
+
+func TestServer_Fail(t *testing.T) {
+	server := NewServer(testCreds)
+	defer server.Close()
+
+	server.AddZone("zone1.org.")
+	server.SetFail(func(r *http.Request) int {
+		if r.Method == http.MethodPost && r.URL.Path != authPath {
+			return http.StatusInternalServerError
+		}
+
+		return 0
+	})
+
+	provider := selectel.NewProvider(selectel.NewClient(testCreds, server.Options()...))
+	_, err := provider.AppendRecords(context.Background(), "zone1.org.", []libdns.Record{
+		libdns.TXT{Name: "rrset1", Text: "HELLO"},
+	})
+	assert.ErrorContains(t, err, "injected failure")
+	assert.Empty(t, server.RRSets("zone1.org."))
+}
+
+func TestServer_InvalidCredentials(t *testing.T) {
+	server := NewServer(testCreds)
+	defer server.Close()
+
+	creds := testCreds
+	creds.Password = "invalid"
+	_, err := selectel.NewClient(creds, server.Options()...).GetZones(context.Background())
+	assert.ErrorContains(t, err, "invalid credentials")
+}
+
+func issueToken(t *testing.T, server *Server) string {
+	t.Helper()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	token := newToken()
+	server.tokens[token] = time.Now().Add(server.tokenTTL)
+	return token
+}
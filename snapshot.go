@@ -0,0 +1,136 @@
+package selectel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the snapshot file format written by Snapshot.Write.
+const SnapshotVersion = 1
+
+// Snapshot is the full state of a zone, including disabled records and RR set IDs.
+type Snapshot struct {
+	Zone   string
+	Time   time.Time
+	RRSets map[RRSetKey]*RRSet
+}
+
+type snapshotFile struct {
+	Version int             `json:"version"`
+	Zone    string          `json:"zone"`
+	Time    time.Time       `json:"time"`
+	RRSets  []snapshotRRSet `json:"rrsets"`
+}
+
+type snapshotRRSet struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// TTL in seconds.
+	TTL      int      `json:"ttl"`
+	Records  []string `json:"records,omitempty"`
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// TakeSnapshot reads the current state of the zone.
+func TakeSnapshot(ctx context.Context, client Client, zone string) (*Snapshot, error) {
+	sets, err := client.GetRRSets(ctx, zone)
+	if err != nil {
+		return nil, errors.Wrap(err, "get RR sets")
+	}
+
+	return &Snapshot{
+		Zone:   zone,
+		Time:   time.Now().UTC(),
+		RRSets: sets,
+	}, nil
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var file snapshotFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errors.Wrap(err, "decode")
+	}
+
+	if file.Version != SnapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d", file.Version)
+	}
+
+	if file.Zone == "" {
+		return nil, errors.New("zone is required")
+	}
+
+	snapshot := &Snapshot{
+		Zone:   file.Zone,
+		Time:   file.Time,
+		RRSets: make(map[RRSetKey]*RRSet, len(file.RRSets)),
+	}
+
+	for _, fs := range file.RRSets {
+		set := &RRSet{
+			Key: RRSetKey{Name: normalizeName(fs.Name, file.Zone), Type: fs.Type},
+			ID:  fs.ID,
+			TTL: time.Duration(fs.TTL) * time.Second,
+			RRs: RRs{SetOf(fs.Records...), SetOf(fs.Disabled...)},
+		}
+
+		if _, ok := snapshot.RRSets[set.Key]; ok {
+			return nil, errors.Errorf("duplicate RR set %s", set.Key)
+		}
+
+		snapshot.RRSets[set.Key] = set
+	}
+
+	return snapshot, nil
+}
+
+// Write writes the snapshot to w as JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	file := snapshotFile{
+		Version: SnapshotVersion,
+		Zone:    s.Zone,
+		Time:    s.Time,
+		RRSets:  make([]snapshotRRSet, 0, len(s.RRSets)),
+	}
+
+	for _, key := range sortedKeys(s.RRSets) {
+		set := s.RRSets[key]
+		file.RRSets = append(file.RRSets, snapshotRRSet{
+			ID:       set.ID,
+			Name:     key.Name,
+			Type:     key.Type,
+			TTL:      int(set.TTL.Seconds()),
+			Records:  sorted(set.RRs[enabled]),
+			Disabled: sorted(set.RRs[disabled]),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Plan computes the minimal changes restoring the snapshot over current RR sets
+// (as returned by Client.GetRRSets), disabled records included.
+// SOA and apex NS RR sets are managed by DNS hosting and are left as is.
+func (s *Snapshot) Plan(current map[RRSetKey]*RRSet) Plan {
+	var opts ImportOptions
+	return DiffAll(opts.Skip(current), opts.Skip(s.RRSets))
+}
+
+// Restore restores the snapshot in its zone with client and returns the applied changes.
+// RR sets deleted since the snapshot are recreated with new IDs.
+func (s *Snapshot) Restore(ctx context.Context, client Client) (Plan, error) {
+	current, err := client.GetRRSets(ctx, s.Zone)
+	if err != nil {
+		return nil, errors.Wrap(err, "get RR sets")
+	}
+
+	plan := s.Plan(current)
+	return plan, plan.Apply(ctx, client, s.Zone)
+}
@@ -0,0 +1,78 @@
+package selectel_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	selectel "github.com/jfk9w-go/libdns-selectel"
+	"github.com/jfk9w-go/libdns-selectel/selecteltest"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+	www := selectel.RRSetKey{Name: "www", Type: "A"}
+	txt := selectel.RRSetKey{Name: "@", Type: "TXT"}
+	mail := selectel.RRSetKey{Name: "mail", Type: "A"}
+	soa := selectel.RRSetKey{Name: "@", Type: "SOA"}
+	ns := selectel.RRSetKey{Name: "@", Type: "NS"}
+
+	client := selecteltest.NewClient("zone1.org.")
+	require.NoError(t, client.Put("zone1.org.",
+		&selectel.RRSet{Key: soa, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("a.ns.selectel.ru. support.selectel.ru. 1 10800 3600 604800 60"), nil}},
+		&selectel.RRSet{Key: ns, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("a.ns.selectel.ru.", "b.ns.selectel.ru."), nil}},
+		&selectel.RRSet{Key: www, TTL: time.Hour, RRs: selectel.RRs{selectel.SetOf("1.1.1.1"), selectel.SetOf("2.2.2.2")}},
+		&selectel.RRSet{Key: txt, TTL: time.Minute, RRs: selectel.RRs{nil, selectel.SetOf("v=spf1 -all")}},
+	))
+
+	snapshot, err := selectel.TakeSnapshot(ctx, client, "zone1.org.")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Write(&buf))
+	snapshot, err = selectel.ReadSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "zone1.org.", snapshot.Zone)
+	assert.Empty(t, snapshot.Plan(client.RRSets("zone1.org.")))
+	assert.Equal(t, client.RRSets("zone1.org.")[www].ID, snapshot.RRSets[www].ID)
+
+	sets := client.RRSets("zone1.org.")
+	sets[soa].RRs = selectel.RRs{selectel.SetOf("a.ns.selectel.ru. support.selectel.ru. 2 10800 3600 604800 60"), nil}
+	require.NoError(t, client.UpdateRRSet(ctx, "zone1.org.", sets[soa]))
+	sets[ns].RRs = selectel.RRs{selectel.SetOf("a.ns.selectel.ru."), nil}
+	require.NoError(t, client.UpdateRRSet(ctx, "zone1.org.", sets[ns]))
+	sets[www].RRs = selectel.RRs{selectel.SetOf("1.1.1.1", "2.2.2.2"), nil}
+	require.NoError(t, client.UpdateRRSet(ctx, "zone1.org.", sets[www]))
+	require.NoError(t, client.DeleteRRSet(ctx, "zone1.org.", sets[txt].ID))
+	require.NoError(t, client.CreateRRSet(ctx, "zone1.org.", &selectel.RRSet{
+		Key: mail,
+		TTL: time.Hour,
+		RRs: selectel.RRs{selectel.SetOf("3.3.3.3"), nil},
+	}))
+
+	plan, err := snapshot.Restore(ctx, client)
+	require.NoError(t, err)
+
+	var diff strings.Builder
+	require.NoError(t, plan.Format(&diff))
+	assert.Equal(t, "+ ; disabled: @\t60\tIN\tTXT\t\"v=spf1 -all\"\n"+
+		"- mail\t3600\tIN\tA\t3.3.3.3\n"+
+		"- www\t3600\tIN\tA\t2.2.2.2\n"+
+		"+ ; disabled: www\t3600\tIN\tA\t2.2.2.2\n", diff.String())
+
+	sets = client.RRSets("zone1.org.")
+	assert.Empty(t, snapshot.Plan(sets))
+	assert.NotContains(t, sets, mail)
+	assert.Equal(t, selectel.SetOf("a.ns.selectel.ru. support.selectel.ru. 2 10800 3600 604800 60"), sets[soa].RRs[0])
+	assert.Equal(t, selectel.SetOf("a.ns.selectel.ru."), sets[ns].RRs[0])
+	assert.Equal(t, selectel.SetOf("v=spf1 -all"), sets[txt].RRs[1])
+	assert.Equal(t, selectel.RRs{selectel.SetOf("1.1.1.1"), selectel.SetOf("2.2.2.2")}, sets[www].RRs)
+
+	_, err = selectel.ReadSnapshot(strings.NewReader(`{"version": 2, "zone": "zone1.org."}`))
+	assert.EqualError(t, err, "unsupported snapshot version 2")
+}
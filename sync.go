@@ -0,0 +1,166 @@
+package selectel
+
+import (
+	"io"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DesiredState describes the desired state of a zone, e.g. kept in a git repository.
+// It is read from YAML or JSON with ReadDesiredState:
+//
+//	zone: example.com.
+//	ttl: 3600
+//	rrsets:
+//	  - name: www
+//	    type: A
+//	    values: [1.1.1.1, 2.2.2.2]
+//	ignore:
+//	  - name: _acme-challenge*
+//	    type: TXT
+type DesiredState struct {
+	Zone string `yaml:"zone"`
+	// TTL in seconds used for RR sets without TTL.
+	TTL    int            `yaml:"ttl"`
+	RRSets []DesiredRRSet `yaml:"rrsets"`
+	// Ignore lists RR sets managed elsewhere.
+	Ignore []IgnoreRule `yaml:"ignore"`
+}
+
+// DesiredRRSet describes an RR set in DesiredState.
+type DesiredRRSet struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// TTL in seconds.
+	TTL    int      `yaml:"ttl"`
+	Values []string `yaml:"values"`
+}
+
+// IgnoreRule matches RR sets which are neither created, updated nor deleted by Sync.
+type IgnoreRule struct {
+	// Name is a glob pattern (see path.Match) matched against the name relative to the zone.
+	Name string `yaml:"name"`
+	// Type matches any type if empty.
+	Type string `yaml:"type"`
+}
+
+func (r IgnoreRule) match(key RRSetKey) bool {
+	ok, _ := path.Match(r.Name, key.Name)
+	return ok && (r.Type == "" || r.Type == key.Type)
+}
+
+// ReadDesiredState reads DesiredState from YAML or JSON.
+func ReadDesiredState(r io.Reader) (*DesiredState, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var state DesiredState
+	if err := decoder.Decode(&state); err != nil {
+		return nil, errors.Wrap(err, "decode")
+	}
+
+	if state.Zone == "" {
+		return nil, errors.New("zone is required")
+	}
+
+	for _, rule := range state.Ignore {
+		if _, err := path.Match(rule.Name, ""); err != nil {
+			return nil, errors.Wrapf(err, "ignore rule %s", rule.Name)
+		}
+	}
+
+	return &state, nil
+}
+
+// SyncOptions configures DesiredState.Plan.
+type SyncOptions struct {
+	// Prune enables deleting RR sets missing from the desired state.
+	Prune bool
+	// Ignore lists additional RR sets managed elsewhere.
+	Ignore []IgnoreRule
+}
+
+// Plan computes changes making current RR sets (as returned by Client.GetRRSets) match the desired state.
+// RR sets matching ignore rules are left intact, as well as SOA and apex NS RR sets missing from the desired state.
+// Other RR sets missing from the desired state are deleted only if Prune is set.
+func (s *DesiredState) Plan(current map[RRSetKey]*RRSet, opts SyncOptions) (Plan, error) {
+	var records []libdns.Record
+	for _, set := range s.RRSets {
+		if len(set.Values) == 0 {
+			return nil, errors.Errorf("%s %s has no values", set.Type, set.Name)
+		}
+
+		ttl := set.TTL
+		if ttl == 0 {
+			ttl = s.TTL
+		}
+
+		for _, value := range set.Values {
+			records = append(records, libdns.RR{
+				Name: set.Name,
+				Type: set.Type,
+				TTL:  time.Duration(ttl) * time.Second,
+				Data: value,
+			})
+		}
+	}
+
+	next, err := fromRecords(records, s.Zone, TTLStrict)
+	if err != nil {
+		return nil, errors.Wrap(err, "convert records")
+	}
+
+	resolveTTLs(current, next)
+
+	rules := slices.Concat(s.Ignore, opts.Ignore)
+	ignored := func(key RRSetKey) bool {
+		for _, rule := range rules {
+			if rule.match(key) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// SOA and apex NS RR sets are managed by DNS hosting unless they are listed in the desired state.
+	prev := make(map[RRSetKey]*RRSet, len(current))
+	for key, set := range current {
+		if _, ok := next[key]; !ok && (ImportOptions{}).skip(key.Name, key.Type) || ignored(key) {
+			continue
+		}
+
+		prev[key] = set
+	}
+
+	for _, key := range sortedKeys(next) {
+		if ignored(key) {
+			return nil, errors.Errorf("%s matches an ignore rule", key)
+		}
+	}
+
+	state := next
+	if !opts.Prune {
+		state = overlayRRSets(prev, next)
+	}
+
+	if err := validateRRSets(state, next); err != nil {
+		return nil, errors.Wrap(err, "validate records")
+	}
+
+	var plan Plan
+	for _, change := range Diff(prev, next) {
+		if change.Op == ChangeDelete && !opts.Prune {
+			continue
+		}
+
+		plan = append(plan, change)
+	}
+
+	return plan, nil
+}
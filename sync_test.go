@@ -0,0 +1,129 @@
+package selectel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDesiredState_Plan(t *testing.T) {
+	state, err := ReadDesiredState(strings.NewReader(`
+zone: zone1.org.
+ttl: 3600
+rrsets:
+  - name: www
+    type: A
+    values: [1.1.1.1, 2.2.2.2]
+  - name: "@"
+    type: MX
+    ttl: 300
+    values: ["10 mx.zone1.org."]
+ignore:
+  - name: _acme-challenge*
+    type: TXT
+`))
+	require.NoError(t, err)
+
+	current := map[RRSetKey]*RRSet{
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			ID:  "www",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1")},
+		},
+		{Name: "old", Type: "A"}: {
+			Key: RRSetKey{Name: "old", Type: "A"},
+			ID:  "old",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("3.3.3.3")},
+		},
+		{Name: "_acme-challenge.www", Type: "TXT"}: {
+			Key: RRSetKey{Name: "_acme-challenge.www", Type: "TXT"},
+			ID:  "acme",
+			TTL: time.Minute,
+			RRs: RRs{SetOf("token")},
+		},
+	}
+
+	plan, err := state.Plan(current, SyncOptions{})
+	require.NoError(t, err)
+	var b strings.Builder
+	require.NoError(t, plan.Format(&b))
+	assert.Equal(t, ""+
+		"+ @\t300\tIN\tMX\t10 mx.zone1.org.\n"+
+		"+ www\t3600\tIN\tA\t2.2.2.2\n", b.String())
+
+	plan, err = state.Plan(current, SyncOptions{Prune: true, Ignore: []IgnoreRule{{Name: "old"}}})
+	require.NoError(t, err)
+	assert.Len(t, plan, 2)
+
+	plan, err = state.Plan(current, SyncOptions{Prune: true})
+	require.NoError(t, err)
+	b.Reset()
+	require.NoError(t, plan.Format(&b))
+	assert.Equal(t, ""+
+		"+ @\t300\tIN\tMX\t10 mx.zone1.org.\n"+
+		"- old\t3600\tIN\tA\t3.3.3.3\n"+
+		"+ www\t3600\tIN\tA\t2.2.2.2\n", b.String())
+
+	_, err = state.Plan(current, SyncOptions{Ignore: []IgnoreRule{{Name: "www", Type: "A"}}})
+	assert.EqualError(t, err, "A www matches an ignore rule")
+}
+
+func TestDesiredState_Plan_ApexNS(t *testing.T) {
+	state, err := ReadDesiredState(strings.NewReader(`
+zone: zone1.org.
+rrsets:
+  - name: www
+    type: A
+    ttl: 3600
+    values: [1.1.1.1]
+`))
+	require.NoError(t, err)
+
+	current := map[RRSetKey]*RRSet{
+		{Name: "@", Type: "NS"}: {
+			Key: RRSetKey{Name: "@", Type: "NS"},
+			ID:  "ns",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("ns1.selectel.org.", "ns2.selectel.org.")},
+		},
+		{Name: "@", Type: "SOA"}: {
+			Key: RRSetKey{Name: "@", Type: "SOA"},
+			ID:  "soa",
+			TTL: time.Hour,
+			RRs: RRs{SetOf("ns1.selectel.org. support.selectel.ru. 1 10800 3600 604800 60")},
+		},
+	}
+
+	plan, err := state.Plan(current, SyncOptions{Prune: true})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	assert.Equal(t, ChangeCreate, plan[0].Op)
+	assert.Equal(t, RRSetKey{Name: "www", Type: "A"}, plan[0].Key)
+
+	state.RRSets = append(state.RRSets, DesiredRRSet{Name: "@", Type: "NS", TTL: 3600, Values: []string{"ns1.selectel.org."}})
+	plan, err = state.Plan(current, SyncOptions{Prune: true})
+	require.NoError(t, err)
+	require.Len(t, plan, 2)
+	assert.Equal(t, ChangeUpdate, plan[0].Op)
+	assert.Equal(t, RRSetKey{Name: "@", Type: "NS"}, plan[0].Key)
+}
+
+func TestReadDesiredState(t *testing.T) {
+	state, err := ReadDesiredState(strings.NewReader(`{"zone": "zone1.org.", "rrsets": [{"name": "www", "type": "A", "ttl": 60, "values": ["1.1.1.1"]}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, &DesiredState{
+		Zone:   "zone1.org.",
+		RRSets: []DesiredRRSet{{Name: "www", Type: "A", TTL: 60, Values: []string{"1.1.1.1"}}},
+	}, state)
+
+	_, err = ReadDesiredState(strings.NewReader(`rrsets: []`))
+	assert.EqualError(t, err, "zone is required")
+
+	_, err = ReadDesiredState(strings.NewReader("zone: zone1.org.\nrecords: []"))
+	assert.ErrorContains(t, err, "field records not found")
+}
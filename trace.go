@@ -0,0 +1,50 @@
+package selectel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/jfk9w-go/libdns-selectel"
+
+// span attribute keys
+const (
+	zoneKey       = attribute.Key("selectel.zone")
+	rrsetKey      = attribute.Key("selectel.rrset.key")
+	rrsetIDKey    = attribute.Key("selectel.rrset.id")
+	offsetKey     = attribute.Key("selectel.offset")
+	attemptKey    = attribute.Key("selectel.attempt")
+	httpStatusKey = attribute.Key("http.response.status_code")
+)
+
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		return nil
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// startSpan starts a span if tracer is set.
+// Otherwise the context is returned as is along with a no-op span.
+func startSpan(ctx context.Context, tracer trace.Tracer, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err in span if it is not nil and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,99 @@
+package selectel
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported TTL range for RR sets.
+const (
+	MinTTL = time.Minute
+	MaxTTL = 7 * 24 * time.Hour
+)
+
+// TTLPolicy defines how a single TTL is chosen for an RR set
+// when the records it consists of specify different TTLs.
+// Zero TTL is treated as unspecified by all policies. If no TTL is specified for an RR set,
+// the TTL of the existing RR set is kept and MinTTL is used for new RR sets.
+type TTLPolicy int
+
+const (
+	// TTLMin picks the smallest TTL. TTLs below MinTTL are ignored, TTLs above MaxTTL are clamped.
+	// This is the default policy.
+	TTLMin TTLPolicy = iota
+	// TTLMax picks the largest TTL. TTLs below MinTTL are ignored, TTLs above MaxTTL are clamped.
+	TTLMax
+	// TTLFirst picks the first specified TTL. TTLs below MinTTL are ignored, TTLs above MaxTTL are clamped.
+	TTLFirst
+	// TTLStrict requires all specified TTLs to be equal and within the supported range.
+	TTLStrict
+)
+
+func (p TTLPolicy) String() string {
+	switch p {
+	case TTLMin:
+		return "min"
+	case TTLMax:
+		return "max"
+	case TTLFirst:
+		return "first"
+	case TTLStrict:
+		return "strict"
+	default:
+		return "unknown"
+	}
+}
+
+// apply resolves TTL from the provided values according to the policy.
+// Zero (unspecified) is returned if none of the values is specified.
+func (p TTLPolicy) apply(values ...time.Duration) (time.Duration, error) {
+	var result time.Duration
+	for _, value := range values {
+		if value == 0 {
+			continue
+		}
+
+		if p == TTLStrict {
+			if value < MinTTL || value > MaxTTL {
+				return 0, errors.Errorf("TTL %s is out of range [%s, %s]", value, MinTTL, MaxTTL)
+			}
+
+			if result != 0 && result != value {
+				return 0, errors.Errorf("conflicting TTLs %s and %s", result, value)
+			}
+		}
+
+		if value < MinTTL {
+			continue
+		}
+
+		value = min(value, MaxTTL)
+		switch {
+		case result == 0:
+			result = value
+		case p == TTLMin:
+			result = min(result, value)
+		case p == TTLMax:
+			result = max(result, value)
+		}
+	}
+
+	return result, nil
+}
+
+// resolveTTLs replaces unspecified TTLs of next RR sets with TTLs of the existing RR sets in prev
+// or with MinTTL for new RR sets.
+func resolveTTLs(prev, next map[RRSetKey]*RRSet) {
+	for key, set := range next {
+		if set.TTL != 0 {
+			continue
+		}
+
+		if prev, ok := prev[key]; ok && prev.TTL != 0 {
+			set.TTL = prev.TTL
+		} else {
+			set.TTL = MinTTL
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package selectel
 
 import (
+	"cmp"
+	"maps"
+	"slices"
 	"time"
 )
 
@@ -25,3 +28,7 @@ func SetOf[T comparable](values ...T) Set[T] {
 
 	return set
 }
+
+func sorted[T cmp.Ordered](set Set[T]) []T {
+	return slices.Sorted(maps.Keys(set))
+}
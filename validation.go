@@ -0,0 +1,163 @@
+package selectel
+
+import (
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/libdns/libdns"
+	"go.uber.org/multierr"
+)
+
+// ValidationError describes a problem with records detected before any changes are made to the zone.
+// Mutating Provider methods combine all validation errors found into a single error (see multierr.Errors).
+type ValidationError struct {
+	// Reason describes the problem.
+	Reason string
+	// Records contains the offending records.
+	Records []libdns.Record
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Reason)
+	for i, record := range e.Records {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+
+		rr := record.RR()
+		_, _ = fmt.Fprintf(&b, "%s %s %q", rr.Type, rr.Name, rr.Data)
+	}
+
+	return b.String()
+}
+
+// validateRRSets checks the zone state which is going to be submitted to the API.
+// Only enabled records are validated. If changed is not nil, only records of RR sets from changed
+// and CNAME coexistence at their names are checked, so that existing problems elsewhere in the zone are ignored.
+func validateRRSets(sets, changed map[RRSetKey]*RRSet) (errs error) {
+	keys := sortedKeys(sets)
+	names := make(Set[string])
+	for key := range changed {
+		names[key.Name] = true
+	}
+
+	types := make(map[string][]RRSetKey)
+	for _, key := range keys {
+		set := sets[key]
+		if len(set.RRs[enabled]) == 0 || changed != nil && !names[key.Name] {
+			continue
+		}
+
+		types[key.Name] = append(types[key.Name], key)
+		if _, ok := changed[key]; changed != nil && !ok {
+			continue
+		}
+
+		if key.Type == "CNAME" && isApex(key.Name) {
+			_ = multierr.AppendInto(&errs, &ValidationError{
+				Reason:  "CNAME at zone apex",
+				Records: slices.Collect(set.toRecords()),
+			})
+		}
+
+		for record := range set.toRecords() {
+			if reason := validateData(record.RR()); reason != "" {
+				_ = multierr.AppendInto(&errs, &ValidationError{
+					Reason:  reason,
+					Records: []libdns.Record{record},
+				})
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if key.Type != "CNAME" || len(types[key.Name]) < 2 || !slices.Contains(types[key.Name], key) {
+			continue
+		}
+
+		var records []libdns.Record
+		for _, key := range types[key.Name] {
+			records = slices.AppendSeq(records, sets[key].toRecords())
+		}
+
+		_ = multierr.AppendInto(&errs, &ValidationError{
+			Reason:  "CNAME coexists with other records",
+			Records: records,
+		})
+	}
+
+	return
+}
+
+func validateData(rr libdns.RR) string {
+	switch rr.Type {
+	case "A", "AAAA":
+		ip, err := netip.ParseAddr(rr.Data)
+		switch {
+		case err != nil:
+			return "malformed IP address"
+		case rr.Type == "A" && !ip.Is4():
+			return "A record with non-IPv4 address"
+		case rr.Type == "AAAA" && !ip.Is6():
+			return "AAAA record with non-IPv6 address"
+		}
+
+	case "MX":
+		fields := strings.Fields(rr.Data)
+		if len(fields) != 2 {
+			return "malformed MX data"
+		}
+
+		if _, err := netip.ParseAddr(strings.TrimSuffix(fields[1], ".")); err == nil {
+			return "MX record points to IP address"
+		}
+	}
+
+	return ""
+}
+
+// overlayRRSets returns the zone state after enabled records of RR sets from next replace ones in prev.
+// Neither of the arguments is modified.
+func overlayRRSets(prev, next map[RRSetKey]*RRSet) map[RRSetKey]*RRSet {
+	result := make(map[RRSetKey]*RRSet, len(prev)+len(next))
+	maps.Copy(result, prev)
+	maps.Copy(result, next)
+	return result
+}
+
+// mergeRRSets returns the zone state after enabled records from next are added to prev.
+// Neither of the arguments is modified.
+func mergeRRSets(prev, next map[RRSetKey]*RRSet) map[RRSetKey]*RRSet {
+	result := make(map[RRSetKey]*RRSet, len(prev)+len(next))
+	maps.Copy(result, prev)
+
+	for key, next := range next {
+		prev, ok := result[key]
+		if !ok {
+			result[key] = next
+			continue
+		}
+
+		rrs := make(Set[string])
+		maps.Copy(rrs, prev.RRs[enabled])
+		maps.Copy(rrs, next.RRs[enabled])
+
+		result[key] = &RRSet{
+			Key: key,
+			ID:  prev.ID,
+			TTL: next.TTL,
+			RRs: RRs{
+				enabled:  rrs,
+				disabled: prev.RRs[disabled],
+			},
+		}
+	}
+
+	return result
+}
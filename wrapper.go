@@ -5,13 +5,16 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/pkg/errors"
 	v2 "github.com/selectel/domains-go/pkg/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -19,9 +22,14 @@ const (
 )
 
 type wrapper struct {
-	creds Credentials
-	dns   v2.DNSClient[v2.Zone, v2.RRSet]
-	token struct {
+	creds      Credentials
+	authURL    string
+	httpClient *http.Client
+	log        *slog.Logger
+	tracer     trace.Tracer
+	metrics    *Metrics
+	dns        v2.DNSClient[v2.Zone, v2.RRSet]
+	token      struct {
 		issuedAt  time.Time
 		expiresAt time.Time
 	}
@@ -29,18 +37,30 @@ type wrapper struct {
 	mu sync.RWMutex
 }
 
-func newWrapper(creds Credentials) *wrapper {
+func newWrapper(creds Credentials, opts options) *wrapper {
 	headers := make(http.Header)
 	headers.Set("User-Agent", "libdns/selectel")
 
+	httpClient := *opts.httpClient
+	if httpClient.Transport == nil {
+		httpClient.Transport = http.DefaultTransport
+	}
+
+	httpClient.Transport = transport{next: httpClient.Transport}
+
 	return &wrapper{
-		creds: creds,
-		dns:   v2.NewClient(domainsApiURL, new(http.Client), headers),
+		creds:      creds,
+		authURL:    opts.authURL,
+		httpClient: &httpClient,
+		log:        opts.logger,
+		tracer:     newTracer(opts.tracerProvider),
+		metrics:    opts.metrics,
+		dns:        v2.NewClient(opts.apiURL, &httpClient, headers),
 	}
 }
 
 func (w *wrapper) ListZones(ctx context.Context, params *map[string]string) (result v2.Listable[v2.Zone], err error) {
-	err = w.execute(ctx, func(dns DNSClient) (err error) {
+	err = w.execute(ctx, "ListZones", func(ctx context.Context, dns DNSClient) (err error) {
 		result, err = dns.ListZones(ctx, params)
 		return
 	})
@@ -49,7 +69,7 @@ func (w *wrapper) ListZones(ctx context.Context, params *map[string]string) (res
 }
 
 func (w *wrapper) ListRRSets(ctx context.Context, zoneID string, params *map[string]string) (result v2.Listable[v2.RRSet], err error) {
-	err = w.execute(ctx, func(dns DNSClient) (err error) {
+	err = w.execute(ctx, "ListRRSets", func(ctx context.Context, dns DNSClient) (err error) {
 		result, err = dns.ListRRSets(ctx, zoneID, params)
 		return
 	})
@@ -58,7 +78,7 @@ func (w *wrapper) ListRRSets(ctx context.Context, zoneID string, params *map[str
 }
 
 func (w *wrapper) CreateRRSet(ctx context.Context, zoneID string, rrset v2.Creatable) (result *v2.RRSet, err error) {
-	err = w.execute(ctx, func(dns DNSClient) (err error) {
+	err = w.execute(ctx, "CreateRRSet", func(ctx context.Context, dns DNSClient) (err error) {
 		result, err = dns.CreateRRSet(ctx, zoneID, rrset)
 		return
 	})
@@ -67,14 +87,20 @@ func (w *wrapper) CreateRRSet(ctx context.Context, zoneID string, rrset v2.Creat
 }
 
 func (w *wrapper) UpdateRRSet(ctx context.Context, zoneID string, rrsetid string, rrset v2.Updatable) error {
-	return w.execute(ctx, func(dns DNSClient) (err error) { return dns.UpdateRRSet(ctx, zoneID, rrsetid, rrset) })
+	return w.execute(ctx, "UpdateRRSet", func(ctx context.Context, dns DNSClient) error {
+		return dns.UpdateRRSet(ctx, zoneID, rrsetid, rrset)
+	})
 }
 
 func (w *wrapper) DeleteRRSet(ctx context.Context, zoneID string, rrsetid string) error {
-	return w.execute(ctx, func(dns DNSClient) (err error) { return dns.DeleteRRSet(ctx, zoneID, rrsetid) })
+	return w.execute(ctx, "DeleteRRSet", func(ctx context.Context, dns DNSClient) error {
+		return dns.DeleteRRSet(ctx, zoneID, rrsetid)
+	})
 }
 
-func (w *wrapper) authorize(ctx context.Context, issuedAt time.Time) error {
+// authorize obtains a new token unless it has been already renewed after issuedAt.
+// reason is used for metrics only.
+func (w *wrapper) authorize(ctx context.Context, issuedAt time.Time, reason string) (err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -82,6 +108,11 @@ func (w *wrapper) authorize(ctx context.Context, issuedAt time.Time) error {
 		return nil
 	}
 
+	w.metrics.observeAuthorization(reason)
+
+	ctx, span := startSpan(ctx, w.tracer, "authorize")
+	defer func() { endSpan(span, err) }()
+
 	var in struct {
 		Auth struct {
 			Identity struct {
@@ -119,18 +150,34 @@ func (w *wrapper) authorize(ctx context.Context, issuedAt time.Time) error {
 		return errors.Wrap(err, "marshal body")
 	}
 
-	return retry(ctx, func() error {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, authTokenURL, bytes.NewReader(body))
+	attempt := 0
+	return retry(ctx, w.log, func() error {
+		attempt++
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.authURL, bytes.NewReader(body))
 		if err != nil {
 			return err
 		}
 
-		resp, err := http.DefaultClient.Do(req)
+		span.SetAttributes(attemptKey.Int(attempt))
+		startedAt := time.Now()
+		resp, err := w.httpClient.Do(req)
 		if err != nil {
+			w.metrics.observeRequest("authorize", 0, attempt, time.Since(startedAt))
+			w.log.LogAttrs(ctx, slog.LevelDebug, "authorize",
+				slog.Duration("duration", time.Since(startedAt)),
+				slog.Int("attempt", attempt),
+				errorAttr(err))
 			return err
 		}
 
 		defer discardBody(resp)
+		span.SetAttributes(httpStatusKey.Int(resp.StatusCode))
+		w.metrics.observeRequest("authorize", resp.StatusCode, attempt, time.Since(startedAt))
+		w.log.LogAttrs(ctx, slog.LevelDebug, "authorize",
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", time.Since(startedAt)),
+			slog.Int("attempt", attempt))
+
 		switch {
 		case resp.StatusCode == http.StatusUnauthorized:
 			return backoff.Permanent(errors.New("invalid credentials"))
@@ -163,35 +210,69 @@ func (w *wrapper) authorize(ctx context.Context, issuedAt time.Time) error {
 
 		w.token.issuedAt = out.Token.IssuedAt
 		w.token.expiresAt = out.Token.ExpiresAt.Add(-time.Hour)
+		w.metrics.setTokenExpiry(out.Token.ExpiresAt)
 
 		return nil
 	})
 }
 
-func (w *wrapper) execute(ctx context.Context, fn func(dns DNSClient) error) error {
-	return retry(ctx, func() error {
+func (w *wrapper) execute(ctx context.Context, method string, fn func(ctx context.Context, dns DNSClient) error) error {
+	attempt := 0
+	// a rejected token is renewed only once per call, so that persistent rejections
+	// (e.g. missing project permissions) are reported instead of re-authorizing forever
+	reauthorized := false
+	return retry(ctx, w.log, func() error {
+		attempt++
 		for {
 			w.mu.RLock()
 			dns, token := w.dns, w.token
 			w.mu.RUnlock()
 
 			if token.expiresAt.Before(time.Now()) {
-				if err := w.authorize(ctx, token.issuedAt); err != nil {
-					return err
+				w.log.LogAttrs(ctx, slog.LevelDebug, "token expired", slog.String("method", method))
+				reason := "expired"
+				if token.issuedAt.IsZero() {
+					reason = "initial"
+				}
+
+				if err := w.authorize(ctx, token.issuedAt, reason); err != nil {
+					// authorize retries by itself
+					return backoff.Permanent(err)
 				}
 
 				continue
 			}
 
-			err := fn(dns)
+			var status int
+			startedAt := time.Now()
+			err := fn(context.WithValue(ctx, statusKey{}, &status), dns)
+			w.metrics.observeRequest(method, status, attempt, time.Since(startedAt))
+			if w.tracer != nil {
+				trace.SpanFromContext(ctx).SetAttributes(attemptKey.Int(attempt), httpStatusKey.Int(status))
+			}
+
+			w.log.LogAttrs(ctx, slog.LevelDebug, "api call",
+				slog.String("method", method),
+				slog.Int("status", status),
+				slog.Duration("duration", time.Since(startedAt)),
+				slog.Int("attempt", attempt),
+				errorAttr(err))
+
 			if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 				return err
 			}
 
-			var bad v2.BadResponseError
+			var bad *v2.BadResponseError
 			if errors.As(err, &bad) && bad.Code == http.StatusUnauthorized {
-				if err := w.authorize(ctx, token.issuedAt); err != nil {
-					return err
+				w.log.LogAttrs(ctx, slog.LevelDebug, "token rejected", slog.String("method", method))
+				if reauthorized {
+					return backoff.Permanent(errors.Wrap(err, "token rejected after re-authorization"))
+				}
+
+				reauthorized = true
+				if err := w.authorize(ctx, token.issuedAt, "rejected"); err != nil {
+					// authorize retries by itself
+					return backoff.Permanent(err)
 				}
 
 				continue
@@ -202,15 +283,50 @@ func (w *wrapper) execute(ctx context.Context, fn func(dns DNSClient) error) err
 	})
 }
 
+type statusKey struct{}
+
+// transport stores response status code in the request context for logging (see statusKey)
+// and converts 429 responses with Retry-After header specified in seconds
+// into errors making retry wait for the requested delay.
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if status, ok := req.Context().Value(statusKey{}).(*int); ok {
+		*status = resp.StatusCode
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return resp, nil
+	}
+
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return resp, nil
+	}
+
+	discardBody(resp)
+	return nil, backoff.RetryAfter(seconds)
+}
+
 func discardBody(resp *http.Response) {
 	_, _ = io.Copy(io.Discard, resp.Body)
 	_ = resp.Body.Close()
 }
 
-func retry(ctx context.Context, fn func() error) error {
+func retry(ctx context.Context, log *slog.Logger, fn func() error) error {
 	_, err := backoff.Retry[struct{}](ctx,
 		func() (struct{}, error) { return struct{}{}, fn() },
 		backoff.WithBackOff(backoff.NewExponentialBackOff()),
+		backoff.WithNotify(func(err error, delay time.Duration) {
+			log.LogAttrs(ctx, slog.LevelDebug, "retry", slog.Duration("delay", delay), errorAttr(err))
+		}),
 		backoff.WithMaxTries(3),
 		backoff.WithMaxElapsedTime(5*time.Second),
 	)
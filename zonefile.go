@@ -0,0 +1,122 @@
+package selectel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ExportOptions configures ExportZone.
+type ExportOptions struct {
+	// IncludeDisabled enables writing disabled records as comments.
+	IncludeDisabled bool
+}
+
+// ExportZone writes RR sets of the zone (as returned by Client.GetRRSets) to w as an RFC 1035 master file.
+// Owner names are written relative to $ORIGIN, $TTL is set to the most common TTL.
+// Records are written one per line ordered by name, type, then data.
+func ExportZone(w io.Writer, zone string, sets map[RRSetKey]*RRSet, opts ExportOptions) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "$ORIGIN %s.\n", strings.TrimSuffix(zone, "."))
+	_, _ = fmt.Fprintf(bw, "$TTL %d\n", int(defaultZoneTTL(sets).Seconds()))
+	for _, key := range sortedKeys(sets) {
+		set := sets[key]
+		for _, data := range sorted(set.RRs[enabled]) {
+			_, _ = fmt.Fprintln(bw, formatZoneLine(set, data))
+		}
+
+		if opts.IncludeDisabled {
+			for _, data := range sorted(set.RRs[disabled]) {
+				_, _ = fmt.Fprintf(bw, "; disabled: %s\n", formatZoneLine(set, data))
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+func defaultZoneTTL(sets map[RRSetKey]*RRSet) time.Duration {
+	counts := make(map[time.Duration]int)
+	for _, set := range sets {
+		counts[getTTL(set.TTL)]++
+	}
+
+	result := MinTTL
+	for _, ttl := range slices.Sorted(maps.Keys(counts)) {
+		if counts[ttl] > counts[result] {
+			result = ttl
+		}
+	}
+
+	return result
+}
+
+func formatZoneLine(set *RRSet, data string) string {
+	if set.Key.Type == "TXT" {
+		data = quoteTXT(data)
+	}
+
+	return fmt.Sprintf("%s\t%d\tIN\t%s\t%s", set.Key.Name, int(getTTL(set.TTL).Seconds()), set.Key.Type, data)
+}
+
+// quoteTXT splits TXT data into quoted character strings of at most 255 bytes
+// escaping quotes, backslashes and non-printable characters.
+func quoteTXT(data string) string {
+	var b strings.Builder
+	for i := 0; i == 0 || i < len(data); i += 255 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteByte('"')
+		for _, c := range []byte(data[i:min(i+255, len(data))]) {
+			switch {
+			case c == '"' || c == '\\':
+				b.WriteByte('\\')
+				b.WriteByte(c)
+			case c < ' ' || c == 0x7f:
+				_, _ = fmt.Fprintf(&b, "\\%03d", c)
+			default:
+				b.WriteByte(c)
+			}
+		}
+
+		b.WriteByte('"')
+	}
+
+	return b.String()
+}
+
+// unescapeTXT reverts escaping of a character string in zone file form (\X and \DDD sequences).
+func unescapeTXT(data string) string {
+	var b strings.Builder
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if c != '\\' || i+1 == len(data) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if i+3 < len(data) && isDigit(data[i+1]) && isDigit(data[i+2]) && isDigit(data[i+3]) {
+			code := int(data[i+1]-'0')*100 + int(data[i+2]-'0')*10 + int(data[i+3]-'0')
+			if code <= 0xff {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+
+		b.WriteByte(data[i+1])
+		i++
+	}
+
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,50 @@
+package selectel
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportZone(t *testing.T) {
+	sets := map[RRSetKey]*RRSet{
+		{Name: "@", Type: "MX"}: {
+			Key: RRSetKey{Name: "@", Type: "MX"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("10 mx1.zone1.org.", "20 mx2.zone1.org.")},
+		},
+		{Name: "www", Type: "A"}: {
+			Key: RRSetKey{Name: "www", Type: "A"},
+			TTL: time.Hour,
+			RRs: RRs{SetOf("1.1.1.1"), SetOf("2.2.2.2")},
+		},
+		{Name: "_acme", Type: "TXT"}: {
+			Key: RRSetKey{Name: "_acme", Type: "TXT"},
+			TTL: time.Minute,
+			RRs: RRs{SetOf(`say "hi" \o/`, strings.Repeat("a", 300))},
+		},
+	}
+
+	var b strings.Builder
+	require.NoError(t, ExportZone(&b, "zone1.org.", sets, ExportOptions{}))
+	assert.Equal(t, "$ORIGIN zone1.org.\n"+
+		"$TTL 3600\n"+
+		"@\t3600\tIN\tMX\t10 mx1.zone1.org.\n"+
+		"@\t3600\tIN\tMX\t20 mx2.zone1.org.\n"+
+		"_acme\t60\tIN\tTXT\t\""+strings.Repeat("a", 255)+"\" \""+strings.Repeat("a", 45)+"\"\n"+
+		"_acme\t60\tIN\tTXT\t\"say \\\"hi\\\" \\\\o/\"\n"+
+		"www\t3600\tIN\tA\t1.1.1.1\n", b.String())
+
+	b.Reset()
+	require.NoError(t, ExportZone(&b, "zone1.org", sets, ExportOptions{IncludeDisabled: true}))
+	assert.True(t, strings.HasPrefix(b.String(), "$ORIGIN zone1.org.\n"))
+	assert.True(t, strings.HasSuffix(b.String(), "www\t3600\tIN\tA\t1.1.1.1\n; disabled: www\t3600\tIN\tA\t2.2.2.2\n"))
+}
+
+func TestQuoteTXT(t *testing.T) {
+	assert.Equal(t, `""`, quoteTXT(""))
+	assert.Equal(t, `"a\009b"`, quoteTXT("a\tb"))
+}